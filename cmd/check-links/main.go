@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/SonarBeserk/sophie-go/internal/emote"
+	"github.com/SonarBeserk/sophie-go/internal/linkcheck"
+)
+
+// Config represents the configuration for the bot
+type Config struct {
+	Emotes []emote.Emote `toml:"emote"`
+	Gifs   []emote.Gif   `toml:"gif"`
+}
+
+// Variables used for command line parameters
+var (
+	emotesFile string
+	workers    int
+	timeout    time.Duration
+)
+
+func init() {
+	flag.StringVar(&emotesFile, "emotes", "./emotes.toml", "Path to file containing emotes")
+	flag.IntVar(&workers, "workers", 8, "Number of links to check concurrently")
+	flag.DurationVar(&timeout, "timeout", 15*time.Second, "Timeout for each request")
+	flag.Parse()
+}
+
+func main() {
+	conf, err := loadEmoteMaps(emotesFile)
+	if err != nil {
+		fmt.Printf("Error loading emotes file %s: %v\n", emotesFile, err)
+		os.Exit(1)
+	}
+
+	urls := make([]string, 0, len(conf.Gifs))
+	for _, gif := range conf.Gifs {
+		urls = append(urls, gif.URL)
+	}
+
+	checker := linkcheck.NewChecker(timeout, workers)
+	results := checker.CheckAll(context.Background(), urls)
+
+	dead := 0
+	redirected := 0
+
+	for i, res := range results {
+		verb := conf.Gifs[i].Verb
+
+		switch {
+		case res.Dead():
+			dead++
+			if res.Err != nil {
+				fmt.Printf("DEAD       [%s] %s: %v\n", verb, res.URL, res.Err)
+			} else {
+				fmt.Printf("DEAD       [%s] %s: status %d\n", verb, res.URL, res.StatusCode)
+			}
+		case res.Redirected():
+			redirected++
+			fmt.Printf("REDIRECTED [%s] %s -> %s\n", verb, res.URL, res.FinalURL)
+		}
+	}
+
+	fmt.Printf("\nChecked: %d, Dead: %d, Redirected: %d\n", len(results), dead, redirected)
+
+	if dead > 0 {
+		os.Exit(1)
+	}
+}
+
+func loadEmoteMaps(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var conf Config
+	if _, err := toml.Decode(string(data), &conf); err != nil {
+		return nil, err
+	}
+
+	return &conf, nil
+}
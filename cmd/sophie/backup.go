@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// scheduleBackups backs the database up into dir every interval, keeping the newest keep backups
+func scheduleBackups(ctx context.Context, dir string, interval time.Duration, keep int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if !backupNow(dir, keep, now) {
+				return
+			}
+		}
+	}
+}
+
+// backupOnRequest backs the database up into dir each time requests receives, keeping the newest keep backups
+func backupOnRequest(ctx context.Context, requests <-chan time.Time, dir string, keep int) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-requests:
+			if !backupNow(dir, keep, now) {
+				return
+			}
+		}
+	}
+}
+
+// backupNow backs the database up into dir, returning false once the bot is shutting down
+func backupNow(dir string, keep int, now time.Time) bool {
+	// Count the backup as running work so shutdown waits for it before closing the database
+	if !handlers.start() {
+		return false
+	}
+	defer handlers.done()
+
+	path, err := database.BackupToDir(dir, keep, now)
+	if err != nil {
+		fmt.Printf("Error backing up database: %v\n", err)
+		return true
+	}
+
+	fmt.Printf("Backed up database to %s\n", path)
+	return true
+}
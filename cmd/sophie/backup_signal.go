@@ -0,0 +1,40 @@
+//go:build !windows
+// +build !windows
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// backupSignalName is the signal that backs up a running bot
+const backupSignalName = "SIGUSR1"
+
+// notifyBackupSignal sends the time to requests each time the bot is sent SIGUSR1
+func notifyBackupSignal(ctx context.Context, requests chan<- time.Time) bool {
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc, syscall.SIGUSR1)
+
+	go func() {
+		defer signal.Stop(sc)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sc:
+				select {
+				case requests <- time.Now():
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return true
+}
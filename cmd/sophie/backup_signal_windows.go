@@ -0,0 +1,14 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// backupSignalName is empty as windows has no signal to back up a running bot with
+const backupSignalName = ""
+
+// notifyBackupSignal does nothing on windows, returning false
+func notifyBackupSignal(ctx context.Context, requests chan<- time.Time) bool {
+	return false
+}
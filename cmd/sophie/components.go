@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/SonarBeserk/sophie-go/internal/commands"
+	"github.com/SonarBeserk/sophie-go/internal/embed"
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	// components maps the handler name at the start of a custom id to the function handling clicks on it
+	components map[string]commands.ComponentFunc = map[string]commands.ComponentFunc{
+		commands.ComponentEmoteSelect: commands.HandleEmoteSelect,
+		commands.ComponentEmoteReturn: commands.HandleEmoteReturn,
+		commands.ComponentEmotePage:   commands.HandleEmotePage,
+	}
+)
+
+// interactionCreate dispatches slash commands to their command, and clicks on buttons and select menus to the handler named in their custom id
+func interactionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !handlers.start() {
+		return
+	}
+	defer handlers.done()
+
+	if i.Type == discordgo.InteractionApplicationCommand {
+		err := commands.Safely(func() error {
+			slashCommand(s, i)
+			return nil
+		})
+		if err != nil {
+			fmt.Printf("Error ocurred handling slash command: %v\n", err)
+		}
+		return
+	}
+
+	if i.Type != discordgo.InteractionMessageComponent {
+		return
+	}
+
+	name, args := embed.ParseCustomID(i.MessageComponentData().CustomID)
+
+	handler := components[name]
+	if handler == nil {
+		fmt.Printf("No handler for component %s\n", name)
+		return
+	}
+
+	err := commands.Safely(func() error {
+		return handler(rootCtx, services, s, i, args)
+	})
+	if err != nil {
+		commands.HandleComponentError(rootCtx, services, s, i, name, err)
+	}
+}
+
+// slashCommand runs a slash command as if its options had been typed after its name.
+// Commands reply in the channel, so the interaction is acknowledged and its placeholder response removed.
+func slashCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ApplicationCommandData()
+
+	command, ok := commands.Lookup(data.Name)
+	if !ok {
+		fmt.Printf("No command for slash command %s\n", data.Name)
+		return
+	}
+
+	authorID := ""
+	if i.Member != nil {
+		authorID = i.Member.User.ID
+	} else if i.User != nil {
+		authorID = i.User.ID
+	}
+
+	options := map[string]*discordgo.ApplicationCommandInteractionDataOption{}
+	for _, option := range data.Options {
+		options[option.Name] = option
+	}
+
+	// Options can be filled in any order, so put them back in the order the command takes its arguments
+	parts := []string{data.Name}
+	for _, arg := range command.Arguments {
+		option, ok := options[arg.Name]
+		if !ok {
+			continue
+		}
+
+		switch option.Type {
+		case discordgo.ApplicationCommandOptionUser:
+			parts = append(parts, "<@"+option.Value.(string)+">")
+		case discordgo.ApplicationCommandOptionInteger:
+			parts = append(parts, strconv.FormatInt(option.IntValue(), 10))
+		default:
+			parts = append(parts, option.StringValue())
+		}
+	}
+
+	// Read the options as if they were typed so free text keeps its quotes and spacing
+	content := strings.Join(parts, " ")
+	tokens := commands.Tokens(content)
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+	if err != nil {
+		fmt.Printf("Error ocurred acknowledging slash command %s: %v\n", data.Name, err)
+		return
+	}
+
+	_, err = commands.DefaultRouter.Dispatch(rootCtx, services, s, &commands.Request{
+		MsgParts:  commands.Words(tokens),
+		GuildID:   i.GuildID,
+		AuthorID:  authorID,
+		ChannelID: i.ChannelID,
+		TriggerID: i.ID,
+		Content:   content,
+		Tokens:    tokens,
+	})
+	if err != nil {
+		fmt.Printf("Error ocurred running slash command %s: %v\n", data.Name, err)
+	}
+
+	err = s.InteractionResponseDelete(i.Interaction)
+	if err != nil {
+		fmt.Printf("Error ocurred removing slash command response %s: %v\n", data.Name, err)
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/SonarBeserk/sophie-go/internal/db"
+	"github.com/pkg/errors"
+)
+
+const dbUsage = `Usage: sophie [-db path] [-store driver -store-dsn dsn] db <command> [flags]
+
+Commands:
+  backup  Copy the bolt database to a file
+  export  Write every stat and setting as JSON or CSV
+  import  Load stats and settings from a JSON or CSV export
+  migrate Upgrade the bolt database to the latest schema version
+  version Show the bolt database's schema version
+
+export and import use the store picked by -store, sql stores can be used while the bot runs.
+A bolt database can't be opened while the bot runs, send the bot SIGUSR1 or use -backup-dir to back it up instead.
+`
+
+// runDBCommand runs a database maintenance command, returning the exit code
+func runDBCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, dbUsage)
+		return 2
+	}
+
+	var err error
+
+	switch args[0] {
+	case "backup":
+		err = dbBackup(args[1:])
+	case "export":
+		err = dbExport(args[1:])
+	case "import":
+		err = dbImport(args[1:])
+	case "migrate":
+		err = dbMigrate(args[1:])
+	case "version":
+		err = dbVersion(args[1:])
+	default:
+		fmt.Fprint(os.Stderr, dbUsage)
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+func dbBackup(args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	out := fs.String("out", "", "Path to write the backup to, defaults to a timestamped file next to the database")
+	fs.Parse(args)
+
+	if *out == "" {
+		*out = filepath.Join(filepath.Dir(databaseFile), "sophie-"+time.Now().UTC().Format("20060102-150405")+".db")
+	}
+
+	database, err := db.OpenDatabaseForTool(databaseFile, true)
+	if errors.Cause(err) == db.ErrDatabaseInUse && backupSignalName != "" {
+		return fmt.Errorf("%v, or send it %s to back up while it runs", err, backupSignalName)
+	}
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	size, err := database.BackupFile(*out)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Backed up %s to %s (%d bytes)\n", databaseFile, *out, size)
+	return nil
+}
+
+func dbExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "", "json or csv, defaults to the output file's extension or json")
+	out := fs.String("out", "-", "Path to write the export to, - for stdout")
+	fs.Parse(args)
+
+	f, err := exportFormat(*format, *out)
+	if err != nil {
+		return err
+	}
+
+	store, err := openToolStore(true)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	export, err := store.Export()
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "-" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("error occurred creating %s: %v", *out, err)
+		}
+		defer file.Close()
+
+		w = file
+	}
+
+	if f == "csv" {
+		err = export.WriteCSV(w)
+	} else {
+		err = export.WriteJSON(w)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d stat(s) and %d setting(s)\n", len(export.Stats), len(export.Settings))
+	return nil
+}
+
+func dbImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	format := fs.String("format", "", "json or csv, defaults to the input file's extension or json")
+	in := fs.String("in", "-", "Path to read the export from, - for stdin")
+	replace := fs.Bool("replace", false, "Remove every existing stat and setting before importing")
+	fs.Parse(args)
+
+	f, err := exportFormat(*format, *in)
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+	if *in != "-" {
+		file, err := os.Open(*in)
+		if err != nil {
+			return fmt.Errorf("error occurred opening %s: %v", *in, err)
+		}
+		defer file.Close()
+
+		r = file
+	}
+
+	var export *db.Export
+	if f == "csv" {
+		export, err = db.ReadExportCSV(r)
+	} else {
+		export, err = db.ReadExportJSON(r)
+	}
+	if err != nil {
+		return err
+	}
+
+	store, err := openToolStore(false)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	// Imports are written in the latest format, so a bolt database has to be on the latest schema first
+	if database, ok := store.(*db.Database); ok {
+		_, err = database.Migrate(false)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = store.Import(export, *replace)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %d stat(s) and %d setting(s)\n", len(export.Stats), len(export.Settings))
+	return nil
+}
+
+func dbMigrate(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "Run the migrations and roll them back, showing what would be applied")
+	fs.Parse(args)
+
+	database, err := db.OpenDatabaseForTool(databaseFile, false)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	version, err := database.SchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	applied, err := database.Migrate(*dryRun)
+	if err != nil {
+		return err
+	}
+
+	if len(applied) == 0 {
+		fmt.Printf("Database is already on schema version %d\n", version)
+		return nil
+	}
+
+	verb := "Applied"
+	if *dryRun {
+		verb = "Would apply"
+	}
+
+	for _, m := range applied {
+		fmt.Printf("%s migration %d: %s\n", verb, m.Version, m.Description)
+	}
+
+	return nil
+}
+
+func dbVersion(args []string) error {
+	database, err := db.OpenDatabaseForTool(databaseFile, true)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	version, err := database.SchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Schema version %d, latest is %d\n", version, db.LatestSchemaVersion())
+	return nil
+}
+
+// openToolStore opens the store picked by -store, failing quickly if it is a bolt database the bot has open
+func openToolStore(readOnly bool) (db.Store, error) {
+	if storeDriver == db.StoreBolt || storeDriver == "" {
+		return db.OpenDatabaseForTool(databaseFile, readOnly)
+	}
+
+	return db.OpenStore(storeDriver, storeDSN, "")
+}
+
+// exportFormat picks json or csv from a flag, falling back to a file's extension
+func exportFormat(format string, path string) (string, error) {
+	if format == "" {
+		format = "json"
+		if strings.EqualFold(filepath.Ext(path), ".csv") {
+			format = "csv"
+		}
+	}
+
+	format = strings.ToLower(format)
+	if format != "json" && format != "csv" {
+		return "", fmt.Errorf("unknown format %s, expected json or csv", format)
+	}
+
+	return format, nil
+}
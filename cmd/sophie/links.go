@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SonarBeserk/sophie-go/internal/commands"
+	"github.com/SonarBeserk/sophie-go/internal/linkcheck"
+)
+
+// watchLinks periodically checks every emote image, excluding dead ones from being picked until they recover
+func watchLinks(ctx context.Context, catalog *commands.Catalog, interval time.Duration) {
+	checker := linkcheck.NewChecker(15*time.Second, 4)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	dead := map[string]bool{}
+
+	for {
+		for _, res := range checker.CheckAll(ctx, catalog.ImageURLs()) {
+			if ctx.Err() != nil {
+				return
+			}
+
+			if res.Dead() != dead[res.URL] {
+				if res.Dead() {
+					fmt.Printf("Excluding dead emote image %s: status %d %v\n", res.URL, res.StatusCode, res.Err)
+				} else {
+					fmt.Printf("Emote image recovered %s\n", res.URL)
+				}
+			}
+
+			dead[res.URL] = res.Dead()
+			catalog.SetImageDead(res.URL, res.Dead())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
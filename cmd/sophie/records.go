@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SonarBeserk/sophie-go/internal/db"
+)
+
+// recordedWindow is how long recorded emotes are remembered, long enough to cover retries and redelivered messages
+const recordedWindow = time.Hour
+
+// pruneRecorded forgets emotes recorded longer than recordedWindow ago every interval
+func pruneRecorded(ctx context.Context, stats db.StatsStore, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			// Count pruning as running work so shutdown waits for it before closing the store
+			if !handlers.start() {
+				return
+			}
+
+			pruned, err := stats.PruneRecorded(now.Add(-recordedWindow))
+			handlers.done()
+
+			if err != nil {
+				fmt.Printf("Error pruning recorded emotes: %v\n", err)
+				continue
+			}
+
+			if pruned > 0 {
+				fmt.Printf("Pruned %d recorded emote(s)\n", pruned)
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var (
+	// rootCtx is cancelled at shutdown once running commands finish or the shutdown timeout passes
+	rootCtx context.Context = context.Background()
+
+	handlers *tracker = &tracker{}
+)
+
+// tracker counts running handlers so shutdown can wait for them to finish
+type tracker struct {
+	mu      sync.Mutex
+	closed  bool
+	running int
+	wg      sync.WaitGroup
+}
+
+// start records a handler starting, returning false once the tracker is closed and no new work should begin
+func (t *tracker) start() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return false
+	}
+
+	t.running++
+	t.wg.Add(1)
+	return true
+}
+
+// done records a handler finishing
+func (t *tracker) done() {
+	t.mu.Lock()
+	t.running--
+	t.mu.Unlock()
+
+	t.wg.Done()
+}
+
+// close stops new handlers starting and waits up to timeout for running ones, returning how many are still running
+func (t *tracker) close(timeout time.Duration) int {
+	t.mu.Lock()
+	t.closed = true
+	t.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+	case <-time.After(timeout):
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.running
+}
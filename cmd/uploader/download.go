@@ -0,0 +1,140 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	httpClient *http.Client = &http.Client{Timeout: 60 * time.Second}
+)
+
+// download describes an image fetched from a remote URL
+type download struct {
+	Path        string
+	Key         string
+	SHA256      string
+	Size        int64
+	ContentType string
+}
+
+// permanentError is implemented by errors that retrying won't fix
+type permanentError interface {
+	permanent() bool
+}
+
+// statusError is returned when a remote server responds with a non-200 status
+type statusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status %d fetching %s", e.StatusCode, e.URL)
+}
+
+// client errors other than rate limiting are not worth retrying
+func (e *statusError) permanent() bool {
+	return e.StatusCode >= 400 && e.StatusCode < 500 && e.StatusCode != http.StatusTooManyRequests
+}
+
+// downloadFile fetches an image into directory, naming it by the hash of its contents
+func downloadFile(URL string, directory string, maxSize int64) (*download, error) {
+	response, err := httpClient.Get(URL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error fetching %s", URL)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, &statusError{URL: URL, StatusCode: response.StatusCode}
+	}
+
+	if response.ContentLength > maxSize {
+		return nil, &invalidImageError{URL: URL, Reason: fmt.Sprintf("size %d exceeds the %d byte limit", response.ContentLength, maxSize)}
+	}
+
+	f, err := ioutil.TempFile(directory, "download-*")
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error creating file in %s", directory)
+	}
+
+	// Hash the body while it is written to disk, reading one byte past the limit to detect oversized images
+	hash := sha256.New()
+	size, err := io.Copy(f, io.TeeReader(io.LimitReader(response.Body, maxSize+1), hash))
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, errors.Wrapf(err, "Error writing file %s", f.Name())
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return nil, errors.Wrapf(err, "Error closing file %s", f.Name())
+	}
+
+	if size > maxSize {
+		os.Remove(f.Name())
+		return nil, &invalidImageError{URL: URL, Reason: fmt.Sprintf("size exceeds the %d byte limit", maxSize)}
+	}
+
+	contentType, ext, err := sniffImage(f.Name())
+	if err != nil {
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	if ext == "" {
+		os.Remove(f.Name())
+		return nil, &invalidImageError{URL: URL, Reason: "unsupported content type " + contentType}
+	}
+
+	sum := hex.EncodeToString(hash.Sum(nil))
+	key := sum + ext
+	fileName := filepath.Join(directory, key)
+
+	if err := os.Rename(f.Name(), fileName); err != nil {
+		os.Remove(f.Name())
+		return nil, errors.Wrapf(err, "Error renaming file to %s", fileName)
+	}
+
+	return &download{
+		Path:        fileName,
+		Key:         key,
+		SHA256:      sum,
+		Size:        size,
+		ContentType: contentType,
+	}, nil
+}
+
+// retry runs fn up to attempts times, doubling the delay between each try
+func retry(attempts int, delay time.Duration, fn func() error) error {
+	var err error
+
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+
+		err = fn()
+		if err == nil {
+			return nil
+		}
+
+		if pe, ok := errors.Cause(err).(permanentError); ok && pe.permanent() {
+			return err
+		}
+	}
+
+	return err
+}
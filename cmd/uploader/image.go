@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// imageExtensions maps the image types Discord can embed to their file extensions
+	imageExtensions map[string]string = map[string]string{
+		"image/gif":  ".gif",
+		"image/png":  ".png",
+		"image/webp": ".webp",
+		"image/jpeg": ".jpg",
+	}
+)
+
+// invalidImageError is returned when a downloaded file can't be used as an emote image
+type invalidImageError struct {
+	URL    string
+	Reason string
+}
+
+func (e *invalidImageError) Error() string {
+	return fmt.Sprintf("invalid image %s: %s", e.URL, e.Reason)
+}
+
+// invalid images won't change by downloading them again
+func (e *invalidImageError) permanent() bool {
+	return true
+}
+
+// sniffImage detects the type of an image from its magic bytes, ignoring whatever the server claimed it was
+func sniffImage(fileName string) (contentType string, ext string, err error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "Error opening file %s", fileName)
+	}
+	defer f.Close()
+
+	header := make([]byte, 512)
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", "", errors.Wrapf(err, "Error reading file %s", fileName)
+	}
+
+	contentType = http.DetectContentType(header[:n])
+
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return contentType, "", nil
+	}
+
+	return contentType, ext, nil
+}
@@ -1,19 +1,18 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io/ioutil"
-	"net/http"
 	"net/url"
 	"os"
-	"strings"
+	"sync"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/SonarBeserk/sophie-go/internal/emote"
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/SonarBeserk/sophie-go/internal/storage"
 )
 
 // Config represents the configuration for the bot
@@ -25,27 +24,76 @@ type Config struct {
 // Variables used for command line parameters
 var (
 	emotesFile      string
+	storageType     string
 	bucketName      string
+	prefix          string
+	region          string
+	endpoint        string
+	pathStyle       bool
+	acl             string
+	cacheControl    string
+	localDirectory  string
+	publicURL       string
 	imagesDirectory string
-
-	urlCleaner *strings.Replacer = strings.NewReplacer("https:", "", "/", "", ".", "_")
+	manifestFile    string
+	workers         int
+	attempts        int
+	maxImageSize    int64
+	rewrite         bool
+	rewriteOut      string
+	dryRun          bool
 )
 
+// result is the outcome of mirroring a single image
+type result struct {
+	URL      string
+	Location string
+	Skipped  bool
+	Err      error
+}
+
 func init() {
 	flag.StringVar(&emotesFile, "emotes", "./emotes.toml", "Path to file containing emotes")
+	flag.StringVar(&storageType, "storage", "s3", "Where to upload files to, s3 or local")
 	flag.StringVar(&bucketName, "bucket", "", "The name of the bucket to upload files to")
+	flag.StringVar(&prefix, "prefix", "", "Prefix added to the key of every uploaded file")
+	flag.StringVar(&region, "region", "", "The region of the bucket, defaults to the shared AWS config")
+	flag.StringVar(&endpoint, "endpoint", "", "Custom endpoint for S3-compatible storage such as MinIO or R2")
+	flag.BoolVar(&pathStyle, "path-style", false, "Use path-style bucket addressing, required by most S3-compatible storage")
+	flag.StringVar(&acl, "acl", "public-read", "Canned ACL applied to uploaded files, empty to use the bucket default")
+	flag.StringVar(&cacheControl, "cache-control", storage.DefaultCacheControl, "Cache-Control header stored with uploaded files, the bot's -cache-control serves local files with it")
+	flag.StringVar(&localDirectory, "local-dir", "", "Directory the bot serves images from when using local storage")
+	flag.StringVar(&publicURL, "public-url", "", "Public URL the local directory is served from")
 	flag.StringVar(&imagesDirectory, "images", "images", "Path to download images")
+	flag.StringVar(&manifestFile, "manifest", "./manifest.json", "Path to the manifest of mirrored images")
+	flag.IntVar(&workers, "workers", 4, "Number of images to mirror concurrently")
+	flag.Int64Var(&maxImageSize, "max-size", 8*1024*1024, "Largest image in bytes Discord will embed")
+	flag.IntVar(&attempts, "attempts", 3, "Number of attempts for each download and upload")
+	flag.BoolVar(&rewrite, "rewrite", false, "Rewrite emote urls to their mirrored locations from the manifest instead of uploading")
+	flag.StringVar(&rewriteOut, "out", "", "Path to write the rewritten emotes file to, defaults to rewriting in place")
+	flag.BoolVar(&dryRun, "dry-run", false, "Print the changes -rewrite would make without writing them")
 	flag.Parse()
 }
 
 func main() {
-	// The session the S3 Uploader will use
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
+	manifest, err := LoadManifest(manifestFile)
+	if err != nil {
+		fmt.Printf("Error loading manifest: %v\n", err)
+		return
+	}
 
-	// Create an uploader with the session and default options
-	uploader := s3manager.NewUploader(sess)
+	if rewrite {
+		if rewriteOut == "" {
+			rewriteOut = emotesFile
+		}
+
+		err = rewriteEmotesFile(emotesFile, rewriteOut, manifest, dryRun)
+		if err != nil {
+			fmt.Printf("Error rewriting emotes file %s: %v\n", emotesFile, err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	conf, err := loadEmoteMaps(emotesFile)
 	if err != nil {
@@ -53,6 +101,12 @@ func main() {
 		return
 	}
 
+	store, err := newStorage()
+	if err != nil {
+		fmt.Printf("Error configuring storage: %v\n", err)
+		return
+	}
+
 	if _, err := os.Stat(imagesDirectory); os.IsNotExist(err) {
 		err = os.Mkdir(imagesDirectory, 0755)
 		if err != nil {
@@ -61,49 +115,154 @@ func main() {
 		}
 	}
 
-	for _, image := range conf.Gifs {
-		_, err := url.ParseRequestURI(image.URL)
-		if err != nil {
-			continue
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan emote.Gif)
+	results := make(chan result)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for image := range jobs {
+				results <- mirrorImage(store, manifest, image)
+			}
+		}()
+	}
+
+	go func() {
+		for _, image := range conf.Gifs {
+			jobs <- image
 		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
 
-		fileName := image.Verb + "-" + image.URL
-		fileName = urlCleaner.Replace(fileName)
-		extIndex := strings.LastIndexAny(fileName, "_")
-		fileName = fileName[:extIndex] + "." + fileName[extIndex+1:]
+	uploaded := 0
+	skipped := 0
+	var failures []result
 
-		if strings.Contains(fileName, bucketName) {
-			continue
+	for res := range results {
+		switch {
+		case res.Err != nil:
+			fmt.Printf("Failed to mirror %s: %v\n", res.URL, res.Err)
+			failures = append(failures, res)
+		case res.Skipped:
+			skipped++
+		default:
+			fmt.Printf("File uploaded to: %s\n", res.Location)
+			uploaded++
 		}
+	}
 
-		fmt.Println("Uploading image: " + image.URL)
+	fmt.Printf("\nUploaded: %d, Skipped: %d, Failed: %d\n", uploaded, skipped, len(failures))
+	for _, f := range failures {
+		fmt.Printf("  %s: %v\n", f.URL, f.Err)
+	}
 
-		err = downloadFile(image.URL, imagesDirectory+"/"+fileName)
+	if len(failures) > 0 {
+		os.Exit(1)
+	}
+}
+
+// newStorage creates the storage selected on the command line
+func newStorage() (storage.Storage, error) {
+	switch storageType {
+	case "s3":
+		return storage.NewS3(storage.S3Config{
+			Bucket:       bucketName,
+			Prefix:       prefix,
+			Region:       region,
+			Endpoint:     endpoint,
+			PathStyle:    pathStyle,
+			ACL:          acl,
+			CacheControl: cacheControl,
+		})
+	case "local":
+		return storage.NewLocal(storage.LocalConfig{
+			Directory: localDirectory,
+			Prefix:    prefix,
+			BaseURL:   publicURL,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage type %q", storageType)
+	}
+}
+
+// mirrorImage downloads an image and stores it unless the manifest shows it was already mirrored
+func mirrorImage(store storage.Storage, manifest *Manifest, image emote.Gif) result {
+	res := result{URL: image.URL}
+
+	_, err := url.ParseRequestURI(image.URL)
+	if err != nil {
+		res.Skipped = true
+		return res
+	}
+
+	if _, ok := manifest.Get(image.URL); ok {
+		res.Skipped = true
+		return res
+	}
+
+	if store.Hosts(image.URL) {
+		res.Skipped = true
+		return res
+	}
+
+	fmt.Println("Uploading image: " + image.URL)
+
+	var dl *download
+	err = retry(attempts, time.Second, func() error {
+		d, err := downloadFile(image.URL, imagesDirectory, maxImageSize)
 		if err != nil {
-			fmt.Printf("Failed to download file: %q, %v", fileName, err)
-			return
+			return err
 		}
 
-		f, err := os.Open(imagesDirectory + "/" + fileName)
+		dl = d
+		return nil
+	})
+	if err != nil {
+		res.Err = fmt.Errorf("failed to download file: %v", err)
+		return res
+	}
+
+	var location string
+	err = retry(attempts, time.Second, func() error {
+		f, err := os.Open(dl.Path)
 		if err != nil {
-			fmt.Printf("Failed to open file %q, %v", fileName, err)
-			return
+			return err
 		}
+		defer f.Close()
 
-		// Upload the file to S3.
-		result, err := uploader.Upload(&s3manager.UploadInput{
-			Bucket: aws.String(bucketName),
-			Key:    aws.String(fileName),
-			Body:   f,
-			ACL:    aws.String("public-read"),
+		location, err = store.Put(context.Background(), dl.Key, f, storage.PutOptions{
+			ContentType:  dl.ContentType,
+			CacheControl: cacheControl,
 		})
-		if err != nil {
-			fmt.Printf("Failed to upload file, %v", err)
-			return
-		}
+		return err
+	})
+	if err != nil {
+		res.Err = fmt.Errorf("failed to upload file %q: %v", dl.Key, err)
+		return res
+	}
 
-		fmt.Printf("File uploaded to: %s\n", result.Location)
+	err = manifest.Set(image.URL, ManifestEntry{
+		Key:         dl.Key,
+		SHA256:      dl.SHA256,
+		Size:        dl.Size,
+		ContentType: dl.ContentType,
+		Location:    location,
+	})
+	if err != nil {
+		res.Err = err
+		return res
 	}
+
+	res.Location = location
+	return res
 }
 
 func loadEmoteMaps(path string) (*Config, error) {
@@ -119,24 +278,3 @@ func loadEmoteMaps(path string) (*Config, error) {
 
 	return &conf, nil
 }
-
-func downloadFile(URL, fileName string) error {
-	//Get the response bytes from the url
-	response, err := http.Get(URL)
-	if err != nil {
-	}
-	defer response.Body.Close()
-
-	//Write the bytes to the file
-	data, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return err
-	}
-
-	err = ioutil.WriteFile(fileName, data, 0644)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
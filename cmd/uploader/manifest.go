@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ManifestEntry records where a source image was mirrored to
+type ManifestEntry struct {
+	Key         string `json:"key"`
+	SHA256      string `json:"sha256"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"contentType"`
+	Location    string `json:"location"`
+}
+
+// Manifest maps source image URLs to their mirrored copies
+type Manifest struct {
+	Entries map[string]ManifestEntry `json:"entries"`
+
+	path string
+	mu   sync.Mutex
+}
+
+// LoadManifest reads a manifest from disk, returning an empty one if the file does not exist
+func LoadManifest(path string) (*Manifest, error) {
+	m := &Manifest{
+		Entries: map[string]ManifestEntry{},
+		path:    path,
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error reading manifest %s", path)
+	}
+
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, errors.Wrapf(err, "Error parsing manifest %s", path)
+	}
+
+	if m.Entries == nil {
+		m.Entries = map[string]ManifestEntry{}
+	}
+
+	return m, nil
+}
+
+// Get returns the entry for a source URL if it has already been mirrored
+func (m *Manifest) Get(URL string) (ManifestEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.Entries[URL]
+	return entry, ok
+}
+
+// Set records an entry for a source URL and saves the manifest so an interrupted run can resume
+func (m *Manifest) Set(URL string, entry ManifestEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Entries[URL] = entry
+	return m.save()
+}
+
+// save writes the manifest to disk
+func (m *Manifest) save() error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "Error encoding manifest")
+	}
+
+	return writeFileAtomic(m.path, data, 0644)
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return errors.Wrapf(err, "Error creating temporary file for %s", path)
+	}
+
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return errors.Wrapf(err, "Error setting permissions for %s", path)
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return errors.Wrapf(err, "Error writing temporary file for %s", path)
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return errors.Wrapf(err, "Error closing temporary file for %s", path)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return errors.Wrapf(err, "Error replacing %s", path)
+	}
+
+	return nil
+}
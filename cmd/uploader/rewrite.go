@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	tableHeader = regexp.MustCompile(`^\s*\[\[?\s*([A-Za-z0-9_.-]+)\s*\]\]?`)
+	gifURLLine  = regexp.MustCompile(`^(\s*url\s*=\s*)(['"])([^'"]*)(['"])(.*)$`)
+)
+
+// urlChange describes a single url rewritten in the emotes file
+type urlChange struct {
+	Line int
+	Old  string
+	New  string
+}
+
+// rewriteEmoteURLs replaces [[gif]] urls with their mirrored locations, leaving every other line untouched
+func rewriteEmoteURLs(data string, manifest *Manifest) (string, []urlChange) {
+	lines := strings.Split(data, "\n")
+	changes := []urlChange{}
+	table := ""
+
+	for i, line := range lines {
+		// Preserve Windows line endings when present
+		cr := ""
+		if strings.HasSuffix(line, "\r") {
+			cr = "\r"
+			line = strings.TrimSuffix(line, "\r")
+		}
+
+		if m := tableHeader.FindStringSubmatch(line); m != nil {
+			table = m[1]
+			continue
+		}
+
+		if table != "gif" {
+			continue
+		}
+
+		m := gifURLLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		entry, ok := manifest.Get(m[3])
+		if !ok || entry.Location == "" || entry.Location == m[3] {
+			continue
+		}
+
+		newLine := m[1] + m[2] + entry.Location + m[4] + m[5]
+		changes = append(changes, urlChange{Line: i + 1, Old: line, New: newLine})
+		lines[i] = newLine + cr
+	}
+
+	return strings.Join(lines, "\n"), changes
+}
+
+// rewriteEmotesFile rewrites the urls in path, writing the result to out or printing a diff when dryRun is set
+func rewriteEmotesFile(path string, out string, manifest *Manifest, dryRun bool) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "Error reading emotes file %s", path)
+	}
+
+	rewritten, changes := rewriteEmoteURLs(string(data), manifest)
+
+	if dryRun {
+		for _, c := range changes {
+			fmt.Printf("@@ %s:%d @@\n-%s\n+%s\n", path, c.Line, c.Old, c.New)
+		}
+		fmt.Printf("%d url(s) would be rewritten\n", len(changes))
+		return nil
+	}
+
+	if len(changes) == 0 && out == path {
+		fmt.Println("No urls to rewrite")
+		return nil
+	}
+
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	err = writeFileAtomic(out, []byte(rewritten), perm)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Rewrote %d url(s) in %s\n", len(changes), out)
+	return nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/BurntSushi/toml"
+	"github.com/SonarBeserk/sophie-go/internal/commands"
+	"github.com/SonarBeserk/sophie-go/internal/emote"
+)
+
+// Config represents the configuration for the bot
+type Config struct {
+	Emotes []emote.Emote `toml:"emote"`
+	Gifs   []emote.Gif   `toml:"gif"`
+}
+
+// Variables used for command line parameters
+var (
+	emotesFile string
+	printSlash bool
+)
+
+func init() {
+	flag.StringVar(&emotesFile, "emotes", "./emotes.toml", "Path to file containing emotes")
+	flag.BoolVar(&printSlash, "slash", false, "Print the slash command definitions generated from the commands")
+	flag.Parse()
+}
+
+func main() {
+	conf, err := loadEmoteMaps(emotesFile)
+	if err != nil {
+		fmt.Printf("Error loading emotes file %s: %v\n", emotesFile, err)
+		os.Exit(1)
+	}
+
+	problems := []error{}
+
+	for _, cmd := range commands.Builtins() {
+		if err := commands.Register(cmd); err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	images := map[string]int{}
+	for _, gif := range conf.Gifs {
+		images[gif.Verb]++
+	}
+
+	for _, em := range conf.Emotes {
+		if err := em.Compile(); err != nil {
+			problems = append(problems, err)
+			continue
+		}
+
+		if images[em.Verb] == 0 {
+			problems = append(problems, fmt.Errorf("emote %s has no images", em.Verb))
+		}
+
+		if err := commands.Register(commands.EmoteCommand(em)); err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	problems = append(problems, commands.Validate()...)
+
+	if printSlash {
+		data, err := json.MarshalIndent(commands.ApplicationCommands(), "", "  ")
+		if err != nil {
+			fmt.Printf("Error encoding slash commands: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println(string(data))
+	}
+
+	for _, problem := range problems {
+		fmt.Fprintf(os.Stderr, "INVALID %v\n", problem)
+	}
+
+	fmt.Fprintf(os.Stderr, "\nCommands: %d, Problems: %d\n", len(commands.Commands()), len(problems))
+
+	if len(problems) > 0 {
+		os.Exit(1)
+	}
+}
+
+func loadEmoteMaps(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var conf Config
+	if _, err := toml.Decode(string(data), &conf); err != nil {
+		return nil, err
+	}
+
+	return &conf, nil
+}
@@ -0,0 +1,189 @@
+package commands
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/SonarBeserk/sophie-go/internal/i18n"
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	userMention    = regexp.MustCompile(`^<@!?(\d+)>$`)
+	channelMention = regexp.MustCompile(`^<#(\d+)>$`)
+	snowflake      = regexp.MustCompile(`^\d{15,21}$`)
+)
+
+// UsageError explains why a command's arguments don't match its usage
+type UsageError struct {
+	Key  string
+	Args []interface{}
+}
+
+func (e *UsageError) Error() string {
+	return e.Message(i18n.DefaultLocale)
+}
+
+// Message returns the explanation in a locale
+func (e *UsageError) Message(locale string) string {
+	return i18n.T(locale, e.Key, e.Args...)
+}
+
+// Args holds parsed argument values by name
+type Args struct {
+	values map[string]interface{}
+}
+
+// Has checks if an argument was given
+func (a Args) Has(name string) bool {
+	_, ok := a.values[name]
+	return ok
+}
+
+// String returns a string or rest of line argument, or "" if it wasn't given
+func (a Args) String(name string) string {
+	s, _ := a.values[name].(string)
+	return s
+}
+
+// Int returns an integer argument, or 0 if it wasn't given
+func (a Args) Int(name string) int {
+	n, _ := a.values[name].(int)
+	return n
+}
+
+// Member returns a user argument, or nil if it wasn't given
+func (a Args) Member(name string) *discordgo.Member {
+	m, _ := a.values[name].(*discordgo.Member)
+	return m
+}
+
+// Channel returns a channel argument, or nil if it wasn't given
+func (a Args) Channel(name string) *discordgo.Channel {
+	c, _ := a.values[name].(*discordgo.Channel)
+	return c
+}
+
+// ParseArgs parses a request's words from the nth of its MsgParts into the arguments described by specs, in order.
+// Optional numbers that don't parse are skipped so the word can fill the next argument.
+// Problems with the words are returned as a *UsageError.
+func ParseArgs(svc *Services, s *discordgo.Session, req *Request, specs []Argument, from int) (Args, error) {
+	args := Args{values: map[string]interface{}{}}
+	guildID, authorID := req.GuildID, req.AuthorID
+
+	words := []string{}
+	if from < len(req.MsgParts) {
+		words = req.MsgParts[from:]
+	}
+
+	for _, spec := range specs {
+		if len(words) == 0 {
+			if spec.Required {
+				return args, &UsageError{Key: i18n.MsgArgMissing, Args: []interface{}{spec.Name}}
+			}
+
+			continue
+		}
+
+		word := words[0]
+
+		switch spec.Type {
+		case ArgInteger:
+			n, err := strconv.Atoi(word)
+			if err != nil {
+				if spec.Required {
+					return args, &UsageError{Key: i18n.MsgArgNotNumber, Args: []interface{}{spec.Name, word}}
+				}
+
+				continue
+			}
+
+			args.values[spec.Name] = n
+		case ArgUser:
+			member, err := resolveMember(svc, s, guildID, authorID, word)
+			if err != nil {
+				return args, err
+			}
+
+			if member == nil {
+				return args, &UsageError{Key: i18n.MsgArgNoMember, Args: []interface{}{word}}
+			}
+
+			args.values[spec.Name] = member
+		case ArgChannel:
+			channel, err := resolveChannel(s, guildID, word)
+			if err != nil {
+				return args, err
+			}
+
+			if channel == nil {
+				return args, &UsageError{Key: i18n.MsgArgNoChannel, Args: []interface{}{word}}
+			}
+
+			args.values[spec.Name] = channel
+		case ArgRest:
+			// Free text is taken as it was typed rather than rejoining its words
+			args.values[spec.Name] = req.Rest(len(req.MsgParts) - len(words))
+			words = nil
+			continue
+		default:
+			args.values[spec.Name] = word
+		}
+
+		words = words[1:]
+	}
+
+	if len(words) > 0 {
+		return args, &UsageError{Key: i18n.MsgArgTooMany, Args: []interface{}{strings.Join(words, " ")}}
+	}
+
+	return args, nil
+}
+
+// resolveMember finds a guild member by mention, id or name, returning nil if there is no such member
+func resolveMember(svc *Services, s *discordgo.Session, guildID string, authorID string, word string) (*discordgo.Member, error) {
+	userID := ""
+
+	switch {
+	case strings.EqualFold(word, "me") || strings.EqualFold(word, "myself"):
+		userID = authorID
+	case userMention.MatchString(word):
+		userID = userMention.FindStringSubmatch(word)[1]
+	case snowflake.MatchString(word):
+		userID = word
+	}
+
+	if userID == "" {
+		return svc.Members.FindMember(s, guildID, word)
+	}
+
+	return svc.Members.Member(s, guildID, userID)
+}
+
+// resolveChannel finds a guild channel by mention, id or name, returning nil if there is no such channel
+func resolveChannel(s *discordgo.Session, guildID string, word string) (*discordgo.Channel, error) {
+	channelID := ""
+
+	switch {
+	case channelMention.MatchString(word):
+		channelID = channelMention.FindStringSubmatch(word)[1]
+	case snowflake.MatchString(word):
+		channelID = word
+	}
+
+	channels, err := s.GuildChannels(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimPrefix(word, "#")
+
+	for _, channel := range channels {
+		if channel.ID == channelID || channelID == "" && strings.EqualFold(channel.Name, name) {
+			return channel, nil
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+// Builtins returns the commands that aren't emotes
+func Builtins() []Command {
+	return []Command{
+		{
+			Name:        "help",
+			Description: "List commands or show how to use one",
+			Usage:       "help [command]",
+			Arguments: []Argument{
+				{Name: "command", Description: "The command to explain", Type: ArgString},
+			},
+			Examples: []string{"help", "help hug"},
+			Run:      HandleHelp,
+		},
+		{
+			Name:        "emotes",
+			Description: "List every emote and pick one to send",
+			Usage:       "emotes",
+			Examples:    []string{"emotes"},
+			Run:         HandleListEmotes,
+		},
+		{
+			Name:        "locale",
+			Description: "Show or change the language the bot replies to you or the server in",
+			Usage:       "locale [language|reset]",
+			Arguments: []Argument{
+				{Name: "language", Description: "A language such as de, or reset, or server to change the server's language", Type: ArgRest},
+			},
+			Examples: []string{"locale", "locale de", "locale reset", "locale server fr"},
+			Subcommands: []Command{
+				{
+					Name:        "server",
+					Aliases:     []string{"guild"},
+					Description: "Change the language the bot replies in for the whole server",
+					Usage:       "locale server [language|reset]",
+					Arguments: []Argument{
+						{Name: "language", Description: "A language such as de, or reset", Type: ArgString},
+					},
+					Permission: discordgo.PermissionManageServer,
+					GuildOnly:  true,
+					Run:        HandleLocale,
+				},
+			},
+			Run: HandleLocale,
+		},
+		{
+			Name:        "theme",
+			Description: "Show or change how emotes look in the server",
+			Usage:       "theme",
+			Arguments: []Argument{
+				{Name: "setting", Description: "color, author, avatar or reset", Type: ArgString},
+				{Name: "value", Description: "The new value for the setting", Type: ArgString},
+			},
+			Examples:  []string{"theme", "theme color #ff88cc", "theme author on", "theme reset"},
+			GuildOnly: true,
+			Subcommands: []Command{
+				themeSubcommand("color", []string{"colour"}, "Change the color of emotes", "theme color <#hex|reset>"),
+				themeSubcommand("author", nil, "Show or hide the sender above emotes", "theme author <on|off|reset>"),
+				themeSubcommand("avatar", []string{"thumbnail"}, "Show or hide the receiver's avatar in emotes", "theme avatar <on|off|reset>"),
+				{
+					Name:        "reset",
+					Description: "Go back to each emote's own look",
+					Usage:       "theme reset",
+					Permission:  discordgo.PermissionManageServer,
+					GuildOnly:   true,
+					Run:         HandleTheme,
+				},
+			},
+			Run: HandleTheme,
+		},
+		{
+			Name:        "profile",
+			Description: "Show how many times you have sent and received each emote",
+			Usage:       "profile",
+			Examples:    []string{"profile"},
+			Run:         HandleProfile,
+		},
+		{
+			Name:        "optout",
+			Description: "Show or change whether others can target you with emotes",
+			Usage:       "optout [on|off]",
+			Arguments: []Argument{
+				{Name: "setting", Description: "on to stop others targeting you, off to allow it again", Type: ArgString},
+			},
+			Examples: []string{"optout", "optout on", "optout off"},
+			Run:      HandleOptOut,
+		},
+	}
+}
+
+// themeSubcommand describes a theme setting that takes a value
+func themeSubcommand(name string, aliases []string, description string, usage string) Command {
+	return Command{
+		Name:        name,
+		Aliases:     aliases,
+		Description: description,
+		Usage:       usage,
+		Arguments: []Argument{
+			{Name: "value", Description: "The new value for the setting", Type: ArgString, Required: true},
+		},
+		Permission: discordgo.PermissionManageServer,
+		GuildOnly:  true,
+		Run:        HandleTheme,
+	}
+}
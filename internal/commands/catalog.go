@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/SonarBeserk/sophie-go/internal/emote"
+	"github.com/SonarBeserk/sophie-go/internal/selector"
+)
+
+// Catalog holds the emotes that can be sent and their images
+type Catalog struct {
+	emotes map[string]emote.Emote
+	images map[string][]emote.Gif
+	// names maps verbs and their aliases to the verb
+	names map[string]string
+
+	// selector avoids showing any of the last 3 images for a verb again in the same guild
+	selector *selector.Selector
+
+	deadMu sync.RWMutex
+	dead   map[string]bool
+}
+
+// NewCatalog returns a catalog without any emotes
+func NewCatalog() *Catalog {
+	return &Catalog{
+		emotes:   map[string]emote.Emote{},
+		images:   map[string][]emote.Gif{},
+		names:    map[string]string{},
+		selector: selector.New(3),
+		dead:     map[string]bool{},
+	}
+}
+
+// AddEmote adds an emote under its verb and aliases
+func (c *Catalog) AddEmote(em emote.Emote) {
+	c.emotes[em.Verb] = em
+
+	for _, name := range em.Names() {
+		c.names[name] = em.Verb
+	}
+}
+
+// Emote finds an emote by its verb or one of its aliases
+func (c *Catalog) Emote(name string) (emote.Emote, bool) {
+	verb, ok := c.names[strings.ToLower(name)]
+	if !ok {
+		return emote.Emote{}, false
+	}
+
+	return c.emotes[verb], true
+}
+
+// IsEmote reports whether a name is an emote's verb or alias
+func (c *Catalog) IsEmote(name string) bool {
+	_, ok := c.names[strings.ToLower(name)]
+	return ok
+}
+
+// Verbs returns the verb of every emote sorted alphabetically
+func (c *Catalog) Verbs() []string {
+	verbs := make([]string, 0, len(c.emotes))
+	for verb := range c.emotes {
+		verbs = append(verbs, verb)
+	}
+
+	sort.Strings(verbs)
+	return verbs
+}
+
+// AddImage adds an image for an emote
+func (c *Catalog) AddImage(gif emote.Gif) {
+	c.images[gif.Verb] = append(c.images[gif.Verb], gif)
+}
+
+// Images returns every image for an emote
+func (c *Catalog) Images(verb string) []emote.Gif {
+	return c.images[verb]
+}
+
+// ImageURLs returns the urls of every emote image
+func (c *Catalog) ImageURLs() []string {
+	urls := []string{}
+	for _, images := range c.images {
+		for _, image := range images {
+			urls = append(urls, image.URL)
+		}
+	}
+
+	return urls
+}
+
+// SetImageDead marks an image as dead, excluding it from being picked until it is marked alive again
+func (c *Catalog) SetImageDead(url string, dead bool) {
+	c.deadMu.Lock()
+	defer c.deadMu.Unlock()
+
+	if dead {
+		c.dead[url] = true
+	} else {
+		delete(c.dead, url)
+	}
+}
+
+// liveImages returns the images for an emote that aren't dead, or all of them if every image is dead
+func (c *Catalog) liveImages(verb string) []emote.Gif {
+	c.deadMu.RLock()
+	defer c.deadMu.RUnlock()
+
+	live := []emote.Gif{}
+	for _, image := range c.images[verb] {
+		if !c.dead[image.URL] {
+			live = append(live, image)
+		}
+	}
+
+	if len(live) == 0 {
+		return c.images[verb]
+	}
+
+	return live
+}
@@ -6,5 +6,15 @@ import (
 	"github.com/bwmarrin/discordgo"
 )
 
+// Component handler names used in custom ids
+const (
+	ComponentEmoteSelect = "emote-select"
+	ComponentEmoteReturn = "emote-return"
+	ComponentEmotePage   = "emote-page"
+)
+
 // Func provides a function used to implement a command
-type Func func(ctx context.Context, s *discordgo.Session, msgParts []string, guildID string, authorID string, channelID string) error
+type Func func(ctx context.Context, svc *Services, s *discordgo.Session, msgParts []string, guildID string, authorID string, channelID string) error
+
+// ComponentFunc provides a function used to handle a click on a message component, args hold the state encoded in its custom id
+type ComponentFunc func(ctx context.Context, svc *Services, s *discordgo.Session, i *discordgo.InteractionCreate, args []string) error
@@ -3,83 +3,244 @@ package commands
 import (
 	"context"
 	"fmt"
-	"math/rand"
+	"strconv"
 	"strings"
-	"time"
 
 	"github.com/SonarBeserk/sophie-go/internal/embed"
 	"github.com/SonarBeserk/sophie-go/internal/emote"
-	"github.com/SonarBeserk/sophie-go/internal/helpers"
+	"github.com/SonarBeserk/sophie-go/internal/i18n"
 	"github.com/bwmarrin/discordgo"
 )
 
 var (
-	emotes      map[string]emote.Emote = map[string]emote.Emote{}
-	emoteImages map[string][]string    = map[string][]string{}
+	// emoteArguments are parsed after the optional image number or #tag
+	emoteArguments []Argument = []Argument{
+		{Name: "user", Type: ArgUser},
+		{Name: "message", Type: ArgRest},
+	}
 )
 
-// HandleEmote handles running commands
-func HandleEmote(ctx context.Context, s *discordgo.Session, msgParts []string, guildID string, authorID string, channelID string) error {
+// HandleEmote handles running commands, counting each message or interaction it is run by once
+func HandleEmote(ctx context.Context, svc *Services, s *discordgo.Session, req *Request) error {
+	msgParts, guildID, authorID, channelID := req.MsgParts, req.GuildID, req.AuthorID, req.ChannelID
 	if len(msgParts) < 1 {
 		return nil
 	}
 
-	emote := msgParts[0]
+	em, ok := svc.Catalog.Emote(msgParts[0])
+	if !ok {
+		return nil
+	}
 
-	senderUsr, err := s.GuildMember(guildID, authorID)
+	senderUsr, err := svc.Members.Member(s, guildID, authorID)
 	if err != nil {
-		return fmt.Errorf("error occurred getting username %s %v", authorID, err)
+		return fmt.Errorf("error occurred getting username %s %w", authorID, err)
 	}
 
-	var receiverUsr *discordgo.Member
+	if senderUsr == nil {
+		return nil
+	}
 
-	message := ""
+	// from is the first of msgParts after the emote's name and any image number or #tag
+	from := 1
+	args := msgParts[from:]
+
+	// An image can be requested by its number or narrowed down by a #tag before the user name
+	index := 0
+	tag := ""
+
+	if len(args) > 0 {
+		if n, err := strconv.Atoi(args[0]); err == nil {
+			index = n
+			from++
+		} else if strings.HasPrefix(args[0], "#") && len(args[0]) > 1 {
+			tag = args[0][1:]
+			from++
+		}
+	}
 
-	if len(msgParts) > 1 {
-		userName := msgParts[1]
-		usr, err := helpers.GetUserByName(s, guildID, userName, true)
-		if err != nil {
-			return fmt.Errorf("error occurred getting user by name %s %v", userName, err)
+	parsed, err := ParseArgs(svc, s, req, emoteArguments, from)
+	if err != nil {
+		return err
+	}
+
+	return sendEmote(ctx, svc, s, emoteRequest{
+		Verb:      em.Verb,
+		GuildID:   guildID,
+		ChannelID: channelID,
+		Sender:    senderUsr,
+		Receiver:  parsed.Member("user"),
+		Index:     index,
+		Tag:       tag,
+		Message:   parsed.String("message"),
+		Key:       req.TriggerID,
+	})
+}
+
+// emoteRequest describes an emote to send
+type emoteRequest struct {
+	Verb      string
+	GuildID   string
+	ChannelID string
+	Sender    *discordgo.Member
+	Receiver  *discordgo.Member
+	// Index picks a specific image starting from 1, or a random one when 0
+	Index   int
+	Tag     string
+	Message string
+	// Key identifies what triggered the emote so it is only counted once, empty to always count it
+	Key string
+}
+
+// sendEmote picks an image for an emote, sends it and then records its stats
+func sendEmote(ctx context.Context, svc *Services, s *discordgo.Session, req emoteRequest) error {
+	verb := req.Verb
+	guildID := req.GuildID
+	channelID := req.ChannelID
+	senderUsr := req.Sender
+	receiverUsr := req.Receiver
+	index := req.Index
+	tag := req.Tag
+	authorID := senderUsr.User.ID
+
+	catalog := svc.Catalog
+	allImages := catalog.Images(verb)
+	if len(allImages) == 0 {
+		return nil
+	}
+
+	locale := resolveLocale(svc, s, guildID, authorID)
+	selectorKey := guildID + "|" + verb
+	var image emote.Gif
+	var err error
+
+	switch {
+	case index != 0:
+		if index < 1 || index > len(allImages) {
+			_, err = s.ChannelMessageSend(channelID, i18n.T(locale, i18n.MsgImageOutOfRange, verb, len(allImages)))
+			if err != nil {
+				return fmt.Errorf("error occurred sending message: %w", err)
+			}
+			return nil
 		}
 
-		receiverUsr = usr
+		image = allImages[index-1]
+		catalog.selector.Remember(selectorKey, image.URL)
+	default:
+		images := catalog.liveImages(verb)
+
+		if tag != "" {
+			images = filterImagesByTag(images, tag)
+			if len(images) == 0 {
+				_, err = s.ChannelMessageSend(channelID, i18n.T(locale, i18n.MsgNoTaggedImages, verb, tag))
+				if err != nil {
+					return fmt.Errorf("error occurred sending message: %w", err)
+				}
+				return nil
+			}
+		}
+
+		image, _ = catalog.selector.Pick(selectorKey, images)
 	}
 
-	if len(msgParts) > 2 {
-		message = strings.Join(msgParts[2:], " ")
+	emoteEntry, _ := catalog.Emote(verb)
+
+	target := embed.TargetNone
+	if receiverUsr != nil {
+		switch receiverUsr.User.ID {
+		case authorID:
+			target = embed.TargetSelf
+		case s.State.User.ID:
+			target = embed.TargetBot
+		default:
+			target = embed.TargetUser
+		}
 	}
 
-	// Add randomness
-	rand.Seed(time.Now().UnixNano())
+	if target == embed.TargetUser && optedOut(svc, receiverUsr.User.ID) {
+		_, err = s.ChannelMessageSend(channelID, i18n.T(locale, i18n.MsgOptedOut, receiverUsr.User.Username))
+		if err != nil {
+			return fmt.Errorf("error occurred sending message: %w", err)
+		}
+		return nil
+	}
 
-	if len(emoteImages[emote]) == 0 {
+	if target == embed.TargetSelf && emoteEntry.BlockSelf {
+		_, err = s.ChannelMessageSend(channelID, i18n.T(locale, i18n.MsgCantTargetSelf))
+		if err != nil {
+			return fmt.Errorf("error occurred sending message: %w", err)
+		}
 		return nil
 	}
 
-	r := rand.Intn(len(emoteImages[emote]))
+	receiverID := statsReceiverID(receiverUsr, target)
 
-	image := emoteImages[emote][r]
-	emoteEntry := emotes[emote]
+	counts, err := previewCounts(svc.Stats, verb, authorID, receiverID)
+	if err != nil {
+		return fmt.Errorf("error occurred getting stats: %w", err)
+	}
 
-	embed, err := embed.CreateEmoteEmbed(ctx, emoteEntry, senderUsr, receiverUsr, image, message)
+	emoteEmbed, err := embed.RenderEmoteEmbed(emoteEntry, senderUsr, receiverUsr, target, locale, emoteStyle(svc, guildID, emoteEntry), image.URL, req.Message, counts)
 	if err != nil {
-		return fmt.Errorf("error occurred creating embed: %v", err)
+		return fmt.Errorf("error occurred creating embed: %w", err)
+	}
+
+	msg := embed.NewMessage().AddEmbed(emoteEmbed)
+
+	// Let the receiver send the emote straight back
+	if target == embed.TargetUser {
+		msg.AddButton(i18n.T(locale, i18n.MsgReturnEmote, emoteEntry.LocalizedVerb(locale)), discordgo.PrimaryButton, returnCustomID(svc, verb, senderUsr.User.ID, receiverUsr.User.ID))
 	}
 
-	_, err = s.ChannelMessageSendEmbed(channelID, embed)
+	_, err = msg.Send(s, channelID)
 	if err != nil {
-		return fmt.Errorf("error occurred sending embed: %v", err)
+		return fmt.Errorf("error occurred sending embed: %w", err)
+	}
+
+	// Only emotes Discord accepted are counted, and the key stops a retried command counting twice.
+	// The emote has already been sent so a failure is logged rather than reported to the sender.
+	_, err = svc.Stats.RecordEmote(req.Key, verb, authorID, receiverID)
+	if err != nil {
+		svc.Log.Printf("Error occurred recording %s stats for %s %v\n", verb, authorID, err)
 	}
 
 	return nil
 }
 
-// AddEmote adds an entry to the emotes list
-func AddEmote(emote emote.Emote) {
-	emotes[emote.Verb] = emote
+// EmoteCommand describes the command that sends an emote
+func EmoteCommand(em emote.Emote) Command {
+	names := em.Names()
+	verb := names[0]
+
+	description := em.Description
+	if description == "" {
+		description = fmt.Sprintf("Send a %s emote", verb)
+	}
+
+	return Command{
+		Name:        verb,
+		Aliases:     names[1:],
+		Description: description,
+		Usage:       verb + " [number|#tag] [user] [message]",
+		Arguments: []Argument{
+			{Name: "image", Description: "The number of an image, or a #tag to pick from", Type: ArgString},
+			{Name: "user", Description: "Who to " + verb + ", or me", Type: ArgUser},
+			{Name: "message", Description: "A message to add", Type: ArgRest},
+		},
+		Examples:  []string{verb, verb + " Bob", verb + " 2 Bob", verb + " #cute Bob have a great day"},
+		GuildOnly: true,
+		Handle:    HandleEmote,
+	}
 }
 
-// AddEmoteImage adds an image for an emote
-func AddEmoteImage(gif emote.Gif) {
-	emoteImages[gif.Verb] = append(emoteImages[gif.Verb], gif.URL)
+// filterImagesByTag returns the images tagged with a tag
+func filterImagesByTag(images []emote.Gif, tag string) []emote.Gif {
+	tagged := []emote.Gif{}
+	for _, image := range images {
+		if image.HasTag(tag) {
+			tagged = append(tagged, image)
+		}
+	}
+
+	return tagged
 }
@@ -0,0 +1,197 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/SonarBeserk/sophie-go/internal/i18n"
+	"github.com/bwmarrin/discordgo"
+)
+
+// ErrorKind classifies why a command failed so users get consistent replies
+type ErrorKind int
+
+// Kinds of command error
+const (
+	// ErrorInternal is a bug or problem on the bot's side
+	ErrorInternal ErrorKind = iota
+	// ErrorUser is a mistake in how the command was used
+	ErrorUser
+	// ErrorPermission is the bot lacking permission to do something in Discord
+	ErrorPermission
+	// ErrorDiscord is Discord failing or rejecting a request
+	ErrorDiscord
+	// ErrorCanceled is the bot shutting down before the command finished, not a failure
+	ErrorCanceled
+)
+
+// Discord JSON error codes for missing access or permissions
+const (
+	discordMissingAccess      = 50001
+	discordMissingPermissions = 50013
+)
+
+var (
+	errorMessages map[ErrorKind]string = map[ErrorKind]string{
+		ErrorInternal:   i18n.MsgErrorInternal,
+		ErrorPermission: i18n.MsgErrorPermission,
+		ErrorDiscord:    i18n.MsgErrorDiscord,
+	}
+
+	errorReaction = "⚠️"
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorUser:
+		return "user"
+	case ErrorPermission:
+		return "permission"
+	case ErrorDiscord:
+		return "discord"
+	case ErrorCanceled:
+		return "canceled"
+	default:
+		return "internal"
+	}
+}
+
+// PanicError is returned when a command panics
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Classify works out what kind of problem an error is
+func Classify(err error) ErrorKind {
+	var usageErr *UsageError
+	if errors.As(err, &usageErr) {
+		return ErrorUser
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return ErrorCanceled
+	}
+
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) {
+		if restErr.Message != nil && (restErr.Message.Code == discordMissingAccess || restErr.Message.Code == discordMissingPermissions) {
+			return ErrorPermission
+		}
+
+		if restErr.Response != nil && restErr.Response.StatusCode == http.StatusForbidden {
+			return ErrorPermission
+		}
+
+		return ErrorDiscord
+	}
+
+	return ErrorInternal
+}
+
+// Safely runs fn, turning a panic into a *PanicError
+func Safely(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	return fn()
+}
+
+// Recover stops a panicking command from taking down the bot, turning the panic into an error
+func Recover(next Handler) Handler {
+	return func(ctx context.Context, svc *Services, s *discordgo.Session, req *Request) error {
+		return Safely(func() error {
+			return next(ctx, svc, s, req)
+		})
+	}
+}
+
+// Errors logs failed commands and tells the user something went wrong instead of staying silent.
+// If the reply can't be sent, such as when the bot can't talk in the channel, it reacts to the command instead.
+func Errors(next Handler) Handler {
+	return func(ctx context.Context, svc *Services, s *discordgo.Session, req *Request) error {
+		err := next(ctx, svc, s, req)
+		if err == nil {
+			return nil
+		}
+
+		kind := Classify(err)
+		logError(svc, req.Command.Name, kind, err)
+
+		msgKey, ok := errorMessages[kind]
+		if !ok {
+			return nil
+		}
+
+		locale := resolveLocale(svc, s, req.GuildID, req.AuthorID)
+
+		replyErr := sendReply(s, req.ChannelID, i18n.T(locale, msgKey, req.Command.Name))
+		if replyErr != nil && req.MessageID != "" {
+			replyErr = s.MessageReactionAdd(req.ChannelID, req.MessageID, errorReaction)
+		}
+
+		if replyErr != nil {
+			svc.Log.Printf("Error occurred telling %s command %s failed: %v\n", req.AuthorID, req.Command.Name, replyErr)
+		}
+
+		return nil
+	}
+}
+
+// HandleComponentError logs a failed component click and tells the user who clicked with a message only they can see
+func HandleComponentError(ctx context.Context, svc *Services, s *discordgo.Session, i *discordgo.InteractionCreate, name string, err error) {
+	kind := Classify(err)
+	logError(svc, name, kind, err)
+
+	msgKey, ok := errorMessages[kind]
+	if !ok {
+		return
+	}
+
+	userID := ""
+	if i.Member != nil {
+		userID = i.Member.User.ID
+	} else if i.User != nil {
+		userID = i.User.ID
+	}
+
+	content := i18n.T(resolveLocale(svc, s, i.GuildID, userID), msgKey, name)
+
+	// The interaction may already have been acknowledged, in which case only a followup can be sent
+	if respondEphemeral(s, i, content) == nil {
+		return
+	}
+
+	_, err = s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
+		Content: content,
+		Flags:   discordgo.MessageFlagsEphemeral,
+	})
+	if err != nil {
+		svc.Log.Printf("Error occurred telling %s component %s failed: %v\n", userID, name, err)
+	}
+}
+
+func logError(svc *Services, name string, kind ErrorKind, err error) {
+	if kind == ErrorCanceled {
+		svc.Log.Printf("Stopped running %s as the bot is shutting down\n", name)
+		return
+	}
+
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		svc.Log.Printf("Panic running %s: %v\n%s\n", name, panicErr.Value, panicErr.Stack)
+		return
+	}
+
+	svc.Log.Printf("Error ocurred running %s (%s): %v\n", name, kind, err)
+}
@@ -0,0 +1,138 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/SonarBeserk/sophie-go/internal/embed"
+	"github.com/SonarBeserk/sophie-go/internal/i18n"
+	"github.com/bwmarrin/discordgo"
+)
+
+// ReturnWindow is how long a receiver has to return an emote
+const ReturnWindow = 15 * time.Minute
+
+// returnCustomID encodes who can return an emote and when it was sent
+func returnCustomID(svc *Services, verb string, senderID string, receiverID string) string {
+	return embed.CustomID(ComponentEmoteReturn, verb, senderID, receiverID, strconv.FormatInt(svc.Now().Unix(), 10))
+}
+
+// HandleEmoteReturn sends an emote back to its sender when the original receiver clicks the return button
+func HandleEmoteReturn(ctx context.Context, svc *Services, s *discordgo.Session, i *discordgo.InteractionCreate, args []string) error {
+	clickerID := ""
+	if i.Member != nil {
+		clickerID = i.Member.User.ID
+	} else if i.User != nil {
+		clickerID = i.User.ID
+	}
+
+	locale := resolveLocale(svc, s, i.GuildID, clickerID)
+
+	if len(args) < 4 {
+		return respondEphemeral(s, i, i18n.T(locale, i18n.MsgReturnInvalid))
+	}
+
+	verb, senderID, receiverID := args[0], args[1], args[2]
+
+	if i.Member == nil {
+		return respondEphemeral(s, i, i18n.T(locale, i18n.MsgGuildOnly, verb))
+	}
+
+	// Replies name the emote in the clicker's language when it has a translated name
+	name := verb
+	if em, ok := svc.Catalog.Emote(verb); ok {
+		name = em.LocalizedVerb(locale)
+	}
+
+	if clickerID != receiverID {
+		return respondEphemeral(s, i, i18n.T(locale, i18n.MsgReturnNotYours, name))
+	}
+
+	sentAt, err := strconv.ParseInt(args[3], 10, 64)
+	if err != nil || svc.Now().Sub(time.Unix(sentAt, 0)) > ReturnWindow {
+		err = disableComponent(s, i)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
+			Content: i18n.T(locale, i18n.MsgReturnExpired, name),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		})
+		if err != nil {
+			return fmt.Errorf("error occurred sending followup: %w", err)
+		}
+		return nil
+	}
+
+	sender, err := svc.Members.Member(s, i.GuildID, receiverID)
+	if err != nil {
+		return fmt.Errorf("error occurred getting member %s %w", receiverID, err)
+	}
+
+	receiver, err := svc.Members.Member(s, i.GuildID, senderID)
+	if err != nil {
+		return fmt.Errorf("error occurred getting member %s %w", senderID, err)
+	}
+
+	// The original sender may have left the server since
+	if sender == nil || receiver == nil {
+		return respondEphemeral(s, i, i18n.T(locale, i18n.MsgArgNoMember, "<@"+senderID+">"))
+	}
+
+	// Disable the button first so the emote can only be returned once
+	err = disableComponent(s, i)
+	if err != nil {
+		return err
+	}
+
+	// Keyed by the emote being returned so it is only counted once even if disabling the button failed
+	key := ""
+	if i.Message != nil {
+		key = i.Message.ID
+	}
+
+	return sendEmote(ctx, svc, s, emoteRequest{
+		Verb:      verb,
+		GuildID:   i.GuildID,
+		ChannelID: i.ChannelID,
+		Sender:    sender,
+		Receiver:  receiver,
+		Key:       key,
+	})
+}
+
+// disableComponent updates the clicked message, disabling the button that was clicked
+func disableComponent(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    i.Message.Content,
+			Embeds:     i.Message.Embeds,
+			Components: embed.DisableComponent(i.Message.Components, i.MessageComponentData().CustomID),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error occurred updating message: %w", err)
+	}
+
+	return nil
+}
+
+// respondEphemeral replies to an interaction with a message only the user who clicked can see
+func respondEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, content string) error {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error occurred responding to interaction: %w", err)
+	}
+
+	return nil
+}
@@ -3,23 +3,245 @@ package commands
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/SonarBeserk/sophie-go/internal/embed"
+	"github.com/SonarBeserk/sophie-go/internal/i18n"
 	"github.com/bwmarrin/discordgo"
 )
 
+// HandleHelp lists every command, or explains how to use one
+func HandleHelp(ctx context.Context, svc *Services, s *discordgo.Session, msgParts []string, guildID string, authorID string, channelID string) error {
+	locale := resolveLocale(svc, s, guildID, authorID)
+
+	if len(msgParts) < 2 {
+		_, err := embed.NewMessage().AddEmbed(helpListEmbed(svc.Catalog, locale)).Send(s, channelID)
+		if err != nil {
+			return fmt.Errorf("error occurred sending embed: %w", err)
+		}
+
+		return nil
+	}
+
+	cmd, ok := Lookup(msgParts[1])
+	if !ok {
+		if suggestion, ok := Suggest(msgParts[1], Names()); ok {
+			return sendReply(s, channelID, i18n.T(locale, i18n.MsgDidYouMean, msgParts[1], "help "+suggestion))
+		}
+
+		return sendReply(s, channelID, i18n.T(locale, i18n.MsgHelpUnknown, msgParts[1]))
+	}
+
+	_, err := embed.NewMessage().AddEmbed(helpCommandEmbed(locale, cmd)).Send(s, channelID)
+	if err != nil {
+		return fmt.Errorf("error occurred sending embed: %w", err)
+	}
+
+	return nil
+}
+
+// helpListEmbed lists the built-in commands with their descriptions, followed by the names of every emote
+func helpListEmbed(catalog *Catalog, locale string) *discordgo.MessageEmbed {
+	help := embed.NewEmbed().
+		SetTitle(i18n.T(locale, i18n.MsgHelpTitle)).
+		SetFooter(i18n.T(locale, i18n.MsgHelpFooter)).
+		SetColor(embed.DefaultColor)
+
+	verbs := []string{}
+
+	for _, cmd := range Commands() {
+		if catalog.IsEmote(cmd.Name) {
+			verbs = append(verbs, cmd.Name)
+			continue
+		}
+
+		help.AddField(cmd.Name, cmd.Description)
+	}
+
+	if len(verbs) > 0 {
+		help.AddField(i18n.T(locale, i18n.MsgHelpEmotes), strings.Join(verbs, ", "))
+	}
+
+	return help.Truncate().MessageEmbed
+}
+
+// helpCommandEmbed explains how to use a command
+func helpCommandEmbed(locale string, cmd *Command) *discordgo.MessageEmbed {
+	help := embed.NewEmbed().
+		SetTitle(cmd.Name).
+		SetDescription(cmd.Description).
+		SetColor(embed.DefaultColor).
+		AddField(i18n.T(locale, i18n.MsgHelpUsage), "`"+cmd.Usage+"`")
+
+	if len(cmd.Arguments) > 0 {
+		lines := make([]string, 0, len(cmd.Arguments))
+		for _, arg := range cmd.Arguments {
+			line := "`" + arg.Name + "` " + arg.Description
+			if !arg.Required {
+				line += " (" + i18n.T(locale, i18n.MsgHelpOptional) + ")"
+			}
+
+			lines = append(lines, line)
+		}
+
+		help.AddField(i18n.T(locale, i18n.MsgHelpArguments), strings.Join(lines, "\n"))
+	}
+
+	if len(cmd.Subcommands) > 0 {
+		lines := make([]string, 0, len(cmd.Subcommands))
+		for _, sub := range cmd.Subcommands {
+			lines = append(lines, "`"+sub.Usage+"` "+sub.Description)
+		}
+
+		help.AddField(i18n.T(locale, i18n.MsgHelpSubcommands), strings.Join(lines, "\n"))
+	}
+
+	if len(cmd.Examples) > 0 {
+		help.AddField(i18n.T(locale, i18n.MsgHelpExamples), "`"+strings.Join(cmd.Examples, "`\n`")+"`")
+	}
+
+	if len(cmd.Aliases) > 0 {
+		help.AddField(i18n.T(locale, i18n.MsgHelpAliases), strings.Join(cmd.Aliases, ", "))
+	}
+
+	notes := []string{}
+	if cmd.Permission != 0 {
+		notes = append(notes, i18n.T(locale, i18n.MsgHelpPermission, cmd.PermissionName()))
+	} else {
+		for _, sub := range cmd.Subcommands {
+			if sub.Permission != 0 {
+				notes = append(notes, i18n.T(locale, i18n.MsgHelpPermission, sub.PermissionName()))
+				break
+			}
+		}
+	}
+
+	if cmd.GuildOnly {
+		notes = append(notes, i18n.T(locale, i18n.MsgHelpGuildOnly))
+	}
+
+	if len(notes) > 0 {
+		help.SetFooter(strings.Join(notes, " · "))
+	}
+
+	return help.Truncate().MessageEmbed
+}
+
 // HandleListEmotes handles running commands
-func HandleListEmotes(ctx context.Context, s *discordgo.Session, msgParts []string, guildID string, authorID string, channelID string) error {
-	keys := make([]string, 0, len(emotes))
+func HandleListEmotes(ctx context.Context, svc *Services, s *discordgo.Session, msgParts []string, guildID string, authorID string, channelID string) error {
+	locale := resolveLocale(svc, s, guildID, authorID)
+
+	_, err := emoteListMessage(svc, locale, guildID != "", 0).Send(s, channelID)
+	if err != nil {
+		return fmt.Errorf("error occurred sending embed: %w", err)
+	}
+
+	return nil
+}
+
+// emoteListMessage lists every emote, with a menu to pick one of them starting at offset when pickable is set.
+// A menu only holds so many options, so buttons page through the rest with the offset in their custom ids.
+func emoteListMessage(svc *Services, locale string, pickable bool, offset int) *embed.Message {
+	keys := svc.Catalog.Verbs()
+
+	msg := embed.NewMessage().SetContent(i18n.T(locale, i18n.MsgAvailableEmotes, strings.Join(keys, ", ")))
+
+	// Emotes can only be sent in guilds, so private chats just get the list
+	if !pickable || len(keys) == 0 {
+		return msg
+	}
+
+	if offset < 0 || offset >= len(keys) {
+		offset = 0
+	}
+
+	end := min(offset+embed.ComponentLimitSelectOptions, len(keys))
+
+	options := make([]discordgo.SelectMenuOption, 0, end-offset)
+	for _, key := range keys[offset:end] {
+		options = append(options, discordgo.SelectMenuOption{
+			Label: key,
+			Value: key,
+		})
+	}
+
+	msg.AddSelectMenu(embed.CustomID(ComponentEmoteSelect), i18n.T(locale, i18n.MsgPickEmote), options...)
+
+	if offset > 0 {
+		previous := offset - embed.ComponentLimitSelectOptions
+		if previous < 0 {
+			previous = 0
+		}
+
+		msg.AddButton(i18n.T(locale, i18n.MsgPagePrevious), discordgo.SecondaryButton, embed.CustomID(ComponentEmotePage, strconv.Itoa(previous)))
+	}
+
+	if end < len(keys) {
+		msg.AddButton(i18n.T(locale, i18n.MsgPageNext), discordgo.SecondaryButton, embed.CustomID(ComponentEmotePage, strconv.Itoa(end)))
+	}
+
+	return msg
+}
+
+// HandleEmotePage shows the page of the emotes menu starting at the offset in the clicked button's custom id
+func HandleEmotePage(ctx context.Context, svc *Services, s *discordgo.Session, i *discordgo.InteractionCreate, args []string) error {
+	userID := ""
+	if i.Member != nil {
+		userID = i.Member.User.ID
+	} else if i.User != nil {
+		userID = i.User.ID
+	}
+
+	locale := resolveLocale(svc, s, i.GuildID, userID)
+
+	if len(args) < 1 {
+		return respondEphemeral(s, i, i18n.T(locale, i18n.MsgReturnInvalid))
+	}
 
-	for emote := range emotes {
-		keys = append(keys, emote)
+	offset, err := strconv.Atoi(args[0])
+	if err != nil {
+		return respondEphemeral(s, i, i18n.T(locale, i18n.MsgReturnInvalid))
+	}
+
+	msg := emoteListMessage(svc, locale, i.GuildID != "", offset)
+	if err := msg.Validate(); err != nil {
+		return err
 	}
 
-	_, err := s.ChannelMessageSend(channelID, "Available Emotes: "+strings.Join(keys, ", "))
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    msg.Content,
+			Components: msg.Components,
+		},
+	})
 	if err != nil {
-		return fmt.Errorf("error occurred sending embed: %v", err)
+		return fmt.Errorf("error occurred updating emotes list: %w", err)
 	}
 
 	return nil
 }
+
+// HandleEmoteSelect runs the emote picked from the emotes list for the user who picked it
+func HandleEmoteSelect(ctx context.Context, svc *Services, s *discordgo.Session, i *discordgo.InteractionCreate, args []string) error {
+	values := i.MessageComponentData().Values
+	if len(values) == 0 || i.Member == nil {
+		return nil
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	if err != nil {
+		return fmt.Errorf("error occurred acknowledging interaction: %w", err)
+	}
+
+	return HandleEmote(ctx, svc, s, &Request{
+		MsgParts:  []string{values[0]},
+		GuildID:   i.GuildID,
+		AuthorID:  i.Member.User.ID,
+		ChannelID: i.ChannelID,
+		TriggerID: i.ID,
+	})
+}
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SonarBeserk/sophie-go/internal/i18n"
+	"github.com/bwmarrin/discordgo"
+)
+
+// HandleLocale shows or changes the language used for a user or guild
+func HandleLocale(ctx context.Context, svc *Services, s *discordgo.Session, msgParts []string, guildID string, authorID string, channelID string) error {
+	database := svc.Settings
+	locale := resolveLocale(svc, s, guildID, authorID)
+	args := msgParts[1:]
+	reply := ""
+
+	switch {
+	case len(args) == 0:
+		reply = i18n.T(locale, i18n.MsgLocaleCurrent, locale, guildLocale(svc, s, guildID))
+	case foldName(args[0]) == "server" || foldName(args[0]) == "guild":
+		if guildID == "" {
+			return sendReply(s, channelID, i18n.T(locale, i18n.MsgGuildOnly, "locale server"))
+		}
+
+		allowed, err := checkPermission(svc, s, guildID, authorID, channelID, discordgo.PermissionManageServer)
+		if err != nil || !allowed {
+			return err
+		}
+
+		if len(args) < 2 || foldName(args[1]) == "reset" {
+			err = database.SetGuildLocale(guildID, "")
+			reply = i18n.T(locale, i18n.MsgLocaleGuildReset)
+		} else if !i18n.Valid(args[1]) {
+			reply = i18n.T(locale, i18n.MsgLocaleInvalid, args[1])
+		} else {
+			err = database.SetGuildLocale(guildID, i18n.Normalize(args[1]))
+			reply = i18n.T(resolveLocale(svc, s, guildID, authorID), i18n.MsgLocaleGuildSet, i18n.Normalize(args[1]))
+		}
+
+		if err != nil {
+			return fmt.Errorf("error occurred setting guild locale: %w", err)
+		}
+	case foldName(args[0]) == "reset":
+		err := database.SetUserLocale(authorID, "")
+		if err != nil {
+			return fmt.Errorf("error occurred setting user locale: %w", err)
+		}
+
+		reply = i18n.T(resolveLocale(svc, s, guildID, authorID), i18n.MsgLocaleReset)
+	case !i18n.Valid(args[0]):
+		reply = i18n.T(locale, i18n.MsgLocaleInvalid, args[0])
+	default:
+		newLocale := i18n.Normalize(args[0])
+
+		err := database.SetUserLocale(authorID, newLocale)
+		if err != nil {
+			return fmt.Errorf("error occurred setting user locale: %w", err)
+		}
+
+		reply = i18n.T(newLocale, i18n.MsgLocaleSet, newLocale)
+	}
+
+	_, err := s.ChannelMessageSend(channelID, reply)
+	if err != nil {
+		return fmt.Errorf("error occurred sending message: %w", err)
+	}
+
+	return nil
+}
+
+// resolveLocale finds the locale to reply to a user in, preferring their own choice over the guild's
+func resolveLocale(svc *Services, s *discordgo.Session, guildID string, userID string) string {
+	locale, err := svc.Settings.GetUserLocale(userID)
+	if err != nil {
+		svc.Log.Printf("Error occurred getting locale for user %s %v\n", userID, err)
+	}
+
+	if locale != "" {
+		return locale
+	}
+
+	return guildLocale(svc, s, guildID)
+}
+
+// guildLocale finds the locale for a guild, defaulting to the guild's preferred locale in Discord
+func guildLocale(svc *Services, s *discordgo.Session, guildID string) string {
+	if guildID == "" {
+		return i18n.DefaultLocale
+	}
+
+	locale, err := svc.Settings.GetGuildLocale(guildID)
+	if err != nil {
+		svc.Log.Printf("Error occurred getting locale for guild %s %v\n", guildID, err)
+	}
+
+	if locale != "" {
+		return locale
+	}
+
+	guild, err := s.State.Guild(guildID)
+	if err != nil {
+		if guild, err = s.Guild(guildID); err != nil {
+			return i18n.DefaultLocale
+		}
+	}
+
+	if guild.PreferredLocale == "" {
+		return i18n.DefaultLocale
+	}
+
+	return i18n.Normalize(guild.PreferredLocale)
+}
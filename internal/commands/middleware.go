@@ -0,0 +1,170 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/SonarBeserk/sophie-go/internal/helpers"
+	"github.com/SonarBeserk/sophie-go/internal/i18n"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Logging logs every command that is run
+func Logging(next Handler) Handler {
+	return func(ctx context.Context, svc *Services, s *discordgo.Session, req *Request) error {
+		where := "guild " + req.GuildID
+		if req.Private {
+			where = "private chat"
+		}
+
+		svc.Log.Printf("Running command %s for %s in %s\n", req.Command.Name, req.AuthorID, where)
+		return next(ctx, svc, s, req)
+	}
+}
+
+// Timing logs commands that take at least slow to run
+func Timing(slow time.Duration) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, svc *Services, s *discordgo.Session, req *Request) error {
+			start := time.Now()
+			err := next(ctx, svc, s, req)
+
+			if elapsed := time.Since(start); elapsed >= slow {
+				svc.Log.Printf("Command %s took %s\n", req.Command.Name, elapsed)
+			}
+
+			return err
+		}
+	}
+}
+
+// GuildOnly stops guild only commands from running in private chats
+func GuildOnly(next Handler) Handler {
+	return func(ctx context.Context, svc *Services, s *discordgo.Session, req *Request) error {
+		if req.Private && req.Command.GuildOnly {
+			locale := resolveLocale(svc, s, req.GuildID, req.AuthorID)
+			return sendReply(s, req.ChannelID, i18n.T(locale, i18n.MsgGuildOnly, req.Command.Name))
+		}
+
+		return next(ctx, svc, s, req)
+	}
+}
+
+// Permissions stops users without a command's permission from running it
+func Permissions(next Handler) Handler {
+	return func(ctx context.Context, svc *Services, s *discordgo.Session, req *Request) error {
+		if req.Command.Permission == 0 || req.Private {
+			return next(ctx, svc, s, req)
+		}
+
+		allowed, err := checkPermission(svc, s, req.GuildID, req.AuthorID, req.ChannelID, req.Command.Permission)
+		if err != nil || !allowed {
+			return err
+		}
+
+		return next(ctx, svc, s, req)
+	}
+}
+
+// checkPermission tells the user they can't do something when they lack a permission in the channel.
+// Handlers that write guild settings call it themselves as well as relying on their subcommand's Permission.
+func checkPermission(svc *Services, s *discordgo.Session, guildID string, authorID string, channelID string, permission int64) (bool, error) {
+	allowed, err := helpers.HasPermission(s, authorID, channelID, permission)
+	if err != nil {
+		return false, err
+	}
+
+	if !allowed {
+		locale := resolveLocale(svc, s, guildID, authorID)
+		return false, sendReply(s, channelID, i18n.T(locale, i18n.MsgNoPermission))
+	}
+
+	return true, nil
+}
+
+// Usage replies with what was wrong and the command's usage when it is missing required arguments or returns a *UsageError
+func Usage(next Handler) Handler {
+	return func(ctx context.Context, svc *Services, s *discordgo.Session, req *Request) error {
+		var err error
+
+		for _, arg := range req.Command.Arguments[min(len(req.Args), len(req.Command.Arguments)):] {
+			if arg.Required {
+				err = &UsageError{Key: i18n.MsgArgMissing, Args: []interface{}{arg.Name}}
+				break
+			}
+		}
+
+		if err == nil {
+			err = next(ctx, svc, s, req)
+		}
+
+		var usageErr *UsageError
+		if !errors.As(err, &usageErr) {
+			return err
+		}
+
+		locale := resolveLocale(svc, s, req.GuildID, req.AuthorID)
+		return sendReply(s, req.ChannelID, usageErr.Message(locale)+"\n"+i18n.T(locale, i18n.MsgUsage, req.Command.Usage))
+	}
+}
+
+// rateLimit counts a user's commands in the current window
+type rateLimit struct {
+	start  time.Time
+	count  int
+	warned bool
+}
+
+// RateLimit allows each user to run limit commands every window, warning them once when they go over
+func RateLimit(limit int, window time.Duration) Middleware {
+	var mu sync.Mutex
+	users := map[string]*rateLimit{}
+	lastSweep := time.Now()
+
+	return func(next Handler) Handler {
+		return func(ctx context.Context, svc *Services, s *discordgo.Session, req *Request) error {
+			now := time.Now()
+
+			mu.Lock()
+
+			// Forget users whose windows have ended so the map doesn't grow forever
+			if now.Sub(lastSweep) > window {
+				for userID, rl := range users {
+					if now.Sub(rl.start) > window {
+						delete(users, userID)
+					}
+				}
+				lastSweep = now
+			}
+
+			rl, ok := users[req.AuthorID]
+			if !ok || now.Sub(rl.start) > window {
+				rl = &rateLimit{start: now}
+				users[req.AuthorID] = rl
+			}
+
+			rl.count++
+			limited := rl.count > limit
+			warn := limited && !rl.warned
+			if warn {
+				rl.warned = true
+			}
+			wait := rl.start.Add(window).Sub(now).Round(time.Second)
+
+			mu.Unlock()
+
+			if !limited {
+				return next(ctx, svc, s, req)
+			}
+
+			if !warn {
+				return nil
+			}
+
+			locale := resolveLocale(svc, s, req.GuildID, req.AuthorID)
+			return sendReply(s, req.ChannelID, i18n.T(locale, i18n.MsgRateLimited, wait))
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/SonarBeserk/sophie-go/internal/embed"
+	"github.com/SonarBeserk/sophie-go/internal/i18n"
+	"github.com/bwmarrin/discordgo"
+)
+
+// HandleProfile shows how many times a user has sent and received each emote across every guild
+func HandleProfile(ctx context.Context, svc *Services, s *discordgo.Session, msgParts []string, guildID string, authorID string, channelID string) error {
+	stats := svc.Stats
+	locale := resolveLocale(svc, s, guildID, authorID)
+
+	counts, err := stats.GetUserEmoteCounts(authorID)
+	if err != nil {
+		return fmt.Errorf("error occurred getting emote counts: %w", err)
+	}
+
+	if len(counts) == 0 {
+		return sendReply(s, channelID, i18n.T(locale, i18n.MsgProfileEmpty))
+	}
+
+	author, err := s.User(authorID)
+	if err != nil {
+		return fmt.Errorf("error occurred getting user %s %w", authorID, err)
+	}
+
+	verbs := make([]string, 0, len(counts))
+	for verb := range counts {
+		verbs = append(verbs, verb)
+	}
+
+	sort.Strings(verbs)
+
+	profile := embed.NewEmbed().
+		SetTitle(i18n.T(locale, i18n.MsgProfileTitle, author.Username)).
+		SetThumbnail(author.AvatarURL("")).
+		SetColor(embed.DefaultColor)
+
+	for _, verb := range verbs {
+		c := counts[verb]
+		profile.AddField(verb, i18n.T(locale, i18n.MsgProfileCounts, c.Sent, c.Received))
+	}
+
+	profile.InlineAllFields()
+
+	_, err = embed.NewMessage().AddEmbed(profile.Truncate().MessageEmbed).Send(s, channelID)
+	if err != nil {
+		return fmt.Errorf("error occurred sending embed: %w", err)
+	}
+
+	return nil
+}
+
+// HandleOptOut shows or changes whether others can target a user with emotes
+func HandleOptOut(ctx context.Context, svc *Services, s *discordgo.Session, msgParts []string, guildID string, authorID string, channelID string) error {
+	database := svc.Settings
+	locale := resolveLocale(svc, s, guildID, authorID)
+	args := msgParts[1:]
+
+	if len(args) == 0 {
+		optOut, err := database.GetUserOptOut(authorID)
+		if err != nil {
+			return fmt.Errorf("error occurred getting opt out: %w", err)
+		}
+
+		return sendReply(s, channelID, i18n.T(locale, i18n.MsgOptOutStatus, onOff(locale, optOut)))
+	}
+
+	// Opting out is a yes or no choice, so reset isn't accepted here
+	optOut, ok := parseOnOff(strings.ToLower(args[0]))
+	if !ok || optOut == nil {
+		return &UsageError{Key: i18n.MsgArgNotOnOff, Args: []interface{}{"setting", args[0]}}
+	}
+
+	err := database.SetUserOptOut(authorID, *optOut)
+	if err != nil {
+		return fmt.Errorf("error occurred setting opt out: %w", err)
+	}
+
+	if *optOut {
+		return sendReply(s, channelID, i18n.T(locale, i18n.MsgOptOutOn))
+	}
+
+	return sendReply(s, channelID, i18n.T(locale, i18n.MsgOptOutOff))
+}
+
+// optedOut checks if a user has opted out of being targeted by emotes
+func optedOut(svc *Services, userID string) bool {
+	optOut, err := svc.Settings.GetUserOptOut(userID)
+	if err != nil {
+		svc.Log.Printf("Error occurred getting opt out for user %s %v\n", userID, err)
+		return false
+	}
+
+	return optOut
+}
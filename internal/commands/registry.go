@@ -0,0 +1,211 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Constants for slash command limits
+const (
+	SlashLimitCommands    = 100
+	SlashLimitOptions     = 25
+	SlashLimitDescription = 100
+)
+
+// ArgType is the kind of value an argument takes
+type ArgType int
+
+// Types of argument a command can take
+const (
+	ArgString ArgType = iota
+	ArgInteger
+	// ArgUser takes a member of the guild by mention, id or name, or me for the user running the command
+	ArgUser
+	// ArgChannel takes a channel in the guild by mention, id or name
+	ArgChannel
+	// ArgRest takes the rest of the message
+	ArgRest
+)
+
+var (
+	slashName = regexp.MustCompile(`^[-_\p{Ll}\p{N}]{1,32}$`)
+
+	slashOptionTypes map[ArgType]discordgo.ApplicationCommandOptionType = map[ArgType]discordgo.ApplicationCommandOptionType{
+		ArgString:  discordgo.ApplicationCommandOptionString,
+		ArgInteger: discordgo.ApplicationCommandOptionInteger,
+		ArgUser:    discordgo.ApplicationCommandOptionUser,
+		ArgChannel: discordgo.ApplicationCommandOptionChannel,
+		ArgRest:    discordgo.ApplicationCommandOptionString,
+	}
+
+	permissionNames map[int64]string = map[int64]string{
+		discordgo.PermissionManageServer:   "Manage Server",
+		discordgo.PermissionManageMessages: "Manage Messages",
+		discordgo.PermissionAdministrator:  "Administrator",
+	}
+)
+
+// Argument describes a value a command takes
+type Argument struct {
+	Name        string
+	Description string
+	Type        ArgType
+	Required    bool
+}
+
+// Command describes a command, how to use it and the function that runs it
+type Command struct {
+	Name        string
+	Aliases     []string
+	Description string
+	// Usage shows the arguments in order, such as "hug [number|#tag] [user] [message]"
+	Usage     string
+	Arguments []Argument
+	Examples  []string
+	// Permission is needed to run the command, 0 when anyone can
+	Permission int64
+	// GuildOnly stops the command from being used in private chats
+	GuildOnly bool
+	// Subcommands are picked by the word after the command's name, they share its arguments in slash commands
+	Subcommands []Command
+	Run         Func
+	// Handle runs the command with its whole request, used instead of Run when set
+	Handle Handler
+}
+
+// Names returns the command's name and every alias it can be used by
+func (c Command) Names() []string {
+	return append([]string{c.Name}, c.Aliases...)
+}
+
+// Subcommand finds a subcommand by its name or one of its aliases
+func (c Command) Subcommand(name string) (*Command, bool) {
+	name = foldName(name)
+
+	for i := range c.Subcommands {
+		for _, n := range c.Subcommands[i].Names() {
+			if n == name {
+				return &c.Subcommands[i], true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// foldName is how typed command, subcommand and keyword names are matched.
+// Handlers that look at the same words as the router must use it too, or a word such as ſerver
+// could miss a subcommand and its permission in the router yet still match in the handler.
+func foldName(name string) string {
+	return strings.ToLower(name)
+}
+
+// PermissionName returns a readable name for the command's permission
+func (c Command) PermissionName() string {
+	if name, ok := permissionNames[c.Permission]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("%#x", c.Permission)
+}
+
+// ApplicationCommand returns the slash command definition for the command
+func (c Command) ApplicationCommand() *discordgo.ApplicationCommand {
+	dmPermission := !c.GuildOnly
+
+	cmd := &discordgo.ApplicationCommand{
+		Name:         c.Name,
+		Description:  truncateDescription(c.Description),
+		DMPermission: &dmPermission,
+	}
+
+	for _, arg := range c.Arguments {
+		cmd.Options = append(cmd.Options, &discordgo.ApplicationCommandOption{
+			Type:        slashOptionTypes[arg.Type],
+			Name:        arg.Name,
+			Description: truncateDescription(arg.Description),
+			Required:    arg.Required,
+		})
+	}
+
+	return cmd
+}
+
+// Validate checks that the command has everything help and slash commands need
+func (c Command) Validate() []error {
+	errs := []error{}
+
+	if !slashName.MatchString(c.Name) {
+		errs = append(errs, fmt.Errorf("command %q must be 1-32 lowercase letters, numbers, - or _", c.Name))
+	}
+
+	if c.Description == "" {
+		errs = append(errs, fmt.Errorf("command %s has no description", c.Name))
+	} else if len([]rune(c.Description)) > SlashLimitDescription {
+		errs = append(errs, fmt.Errorf("command %s description is longer than %d characters", c.Name, SlashLimitDescription))
+	}
+
+	if c.Usage == "" {
+		errs = append(errs, fmt.Errorf("command %s has no usage", c.Name))
+	}
+
+	if c.Run == nil && c.Handle == nil {
+		errs = append(errs, fmt.Errorf("command %s has no function to run", c.Name))
+	}
+
+	if len(c.Arguments) > SlashLimitOptions {
+		errs = append(errs, fmt.Errorf("command %s has more than %d arguments", c.Name, SlashLimitOptions))
+	}
+
+	seen := map[string]bool{}
+	optional := false
+
+	for i, arg := range c.Arguments {
+		if !slashName.MatchString(arg.Name) {
+			errs = append(errs, fmt.Errorf("command %s argument %q must be 1-32 lowercase letters, numbers, - or _", c.Name, arg.Name))
+		}
+
+		if seen[arg.Name] {
+			errs = append(errs, fmt.Errorf("command %s has more than one argument named %s", c.Name, arg.Name))
+		}
+		seen[arg.Name] = true
+
+		if arg.Description == "" {
+			errs = append(errs, fmt.Errorf("command %s argument %s has no description", c.Name, arg.Name))
+		}
+
+		// Slash commands need required options first
+		if arg.Required && optional {
+			errs = append(errs, fmt.Errorf("command %s argument %s is required but follows an optional argument", c.Name, arg.Name))
+		}
+		optional = optional || !arg.Required
+
+		if arg.Type == ArgRest && i != len(c.Arguments)-1 {
+			errs = append(errs, fmt.Errorf("command %s argument %s takes the rest of the message but isn't last", c.Name, arg.Name))
+		}
+	}
+
+	for _, sub := range c.Subcommands {
+		if sub.Run == nil && sub.Handle == nil {
+			errs = append(errs, fmt.Errorf("command %s subcommand %s has no function to run", c.Name, sub.Name))
+		}
+
+		if sub.Usage == "" {
+			errs = append(errs, fmt.Errorf("command %s subcommand %s has no usage", c.Name, sub.Name))
+		}
+	}
+
+	return errs
+}
+
+func truncateDescription(description string) string {
+	runes := []rune(description)
+	if len(runes) <= SlashLimitDescription {
+		return description
+	}
+
+	return string(runes[:SlashLimitDescription-1]) + "…"
+}
@@ -0,0 +1,299 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/SonarBeserk/sophie-go/internal/helpers"
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	// DefaultRouter holds the commands registered with the package level functions
+	DefaultRouter *Router = NewRouter()
+)
+
+// Request describes a command being run
+type Request struct {
+	Command *Command
+	// MsgParts holds the words of the message starting with the command's name
+	MsgParts []string
+	// Args holds the words after the command's name, and its subcommand's name if it has one
+	Args      []string
+	GuildID   string
+	AuthorID  string
+	ChannelID string
+	// MessageID is the message the command was sent in, empty for slash commands
+	MessageID string
+	// TriggerID is the message or interaction that ran the command, the same when Discord redelivers it
+	TriggerID string
+	// Content is the text MsgParts were read from, with Tokens saying where each of them starts.
+	// Both are empty when the command wasn't typed, such as when it is run by a button.
+	Content string
+	Tokens  []Token
+	// Private is set when the command was sent in a private chat
+	Private bool
+}
+
+// Rest returns the text from the nth of MsgParts to the end as it was typed, keeping its quotes and spacing.
+// Requests without content join the words with spaces instead.
+func (r *Request) Rest(n int) string {
+	if n >= len(r.MsgParts) {
+		return ""
+	}
+
+	if len(r.Tokens) != len(r.MsgParts) {
+		return strings.Join(r.MsgParts[n:], " ")
+	}
+
+	return strings.TrimRightFunc(r.Content[r.Tokens[n].Start:], unicode.IsSpace)
+}
+
+// Handler runs a request
+type Handler func(ctx context.Context, svc *Services, s *discordgo.Session, req *Request) error
+
+// Middleware wraps a handler with behaviour shared by every command
+type Middleware func(next Handler) Handler
+
+// Router finds the command for a message and runs it through its middleware
+type Router struct {
+	// commands maps command names and aliases to their command
+	commands   map[string]*Command
+	middleware []Middleware
+}
+
+// NewRouter returns a router without any commands
+func NewRouter() *Router {
+	return &Router{
+		commands: map[string]*Command{},
+	}
+}
+
+// Use adds middleware, the first added runs outermost
+func (r *Router) Use(middleware ...Middleware) {
+	r.middleware = append(r.middleware, middleware...)
+}
+
+// Register adds a command under its name and aliases
+func (r *Router) Register(cmd Command) error {
+	cmd.Name = foldName(cmd.Name)
+
+	for i, alias := range cmd.Aliases {
+		cmd.Aliases[i] = foldName(alias)
+	}
+
+	for _, name := range cmd.Names() {
+		if _, exists := r.commands[name]; exists {
+			return fmt.Errorf("command %s uses the name %s which is already taken", cmd.Name, name)
+		}
+	}
+
+	for _, name := range cmd.Names() {
+		r.commands[name] = &cmd
+	}
+
+	return nil
+}
+
+// Lookup finds a command by its name or one of its aliases
+func (r *Router) Lookup(name string) (*Command, bool) {
+	cmd, ok := r.commands[foldName(name)]
+	return cmd, ok
+}
+
+// Commands returns every registered command sorted by name
+func (r *Router) Commands() []*Command {
+	cmds := []*Command{}
+	for name, cmd := range r.commands {
+		if name == cmd.Name {
+			cmds = append(cmds, cmd)
+		}
+	}
+
+	sort.Slice(cmds, func(i, j int) bool {
+		return cmds[i].Name < cmds[j].Name
+	})
+
+	return cmds
+}
+
+// Names returns every name and alias commands can be run by
+func (r *Router) Names() []string {
+	names := make([]string, 0, len(r.commands))
+	for name := range r.commands {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// ApplicationCommands returns the slash command definitions for every registered command
+func (r *Router) ApplicationCommands() []*discordgo.ApplicationCommand {
+	appCmds := []*discordgo.ApplicationCommand{}
+	for _, cmd := range r.Commands() {
+		appCmds = append(appCmds, cmd.ApplicationCommand())
+	}
+
+	return appCmds
+}
+
+// Validate checks every registered command and that there are few enough for slash commands
+func (r *Router) Validate() []error {
+	errs := []error{}
+
+	cmds := r.Commands()
+	for _, cmd := range cmds {
+		errs = append(errs, cmd.Validate()...)
+	}
+
+	if len(cmds) > SlashLimitCommands {
+		errs = append(errs, fmt.Errorf("%d commands are registered but only %d slash commands are allowed", len(cmds), SlashLimitCommands))
+	}
+
+	return errs
+}
+
+// Dispatch runs the command named by the first of the request's MsgParts, returning false if there is no such command.
+// The request's command, arguments and whether it is private are filled in from its message.
+func (r *Router) Dispatch(ctx context.Context, svc *Services, s *discordgo.Session, req *Request) (bool, error) {
+	if len(req.MsgParts) == 0 {
+		return false, nil
+	}
+
+	cmd, ok := r.Lookup(req.MsgParts[0])
+	if !ok {
+		return false, nil
+	}
+
+	// Don't start new commands once the bot is shutting down, skipping them isn't a failure
+	if ctx.Err() != nil {
+		return true, nil
+	}
+
+	args := req.MsgParts[1:]
+
+	// Subcommands get the whole message so they see their arguments where their parent would
+	if len(args) > 0 {
+		if sub, ok := cmd.Subcommand(args[0]); ok {
+			cmd = sub
+			args = args[1:]
+		}
+	}
+
+	req.Command = cmd
+	req.Args = args
+	req.Private = req.GuildID == ""
+
+	var handler Handler = run
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		handler = r.middleware[i](handler)
+	}
+
+	return true, handler(ctx, svc, s, req)
+}
+
+// HandleMessage runs the command in a message addressed to the bot.
+// In guilds messages must start with the bot's name, in private chats it is optional.
+func (r *Router) HandleMessage(ctx context.Context, svc *Services, s *discordgo.Session, m *discordgo.MessageCreate) error {
+	// Ignore all messages created by the bot itself
+	if m.Author == nil || m.Author.ID == s.State.User.ID {
+		return nil
+	}
+
+	isPrivate, err := helpers.IsPrivateChat(s, m.ChannelID)
+	if err != nil {
+		return fmt.Errorf("error occurred verifying channel type %s %w", m.ChannelID, err)
+	}
+
+	tokens := Tokens(m.Content)
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	msgParts := Words(tokens)
+
+	name := strings.ToLower(msgParts[0])
+	addressed := strings.HasPrefix(name, strings.ToLower(s.State.User.Username))
+
+	guildID := m.GuildID
+	if isPrivate {
+		guildID = ""
+	} else {
+		userName, err := helpers.GetUserName(s, m.GuildID, s.State.User.ID)
+		if err != nil {
+			svc.Log.Printf("Error occurred determining guild username %s %v\n", m.GuildID, err)
+		}
+
+		addressed = addressed || strings.HasPrefix(name, strings.ToLower(userName))
+	}
+
+	if addressed {
+		msgParts = msgParts[1:]
+		tokens = tokens[1:]
+	} else if !isPrivate {
+		return nil
+	}
+
+	if len(msgParts) == 0 {
+		return nil
+	}
+
+	found, err := r.Dispatch(ctx, svc, s, &Request{
+		MsgParts:  msgParts,
+		GuildID:   guildID,
+		AuthorID:  m.Author.ID,
+		ChannelID: m.ChannelID,
+		MessageID: m.ID,
+		TriggerID: m.ID,
+		Content:   m.Content,
+		Tokens:    tokens,
+	})
+	if found || !addressed {
+		return err
+	}
+
+	return HandleUnknownCommand(ctx, svc, s, msgParts, guildID, m.Author.ID, m.ChannelID, r.Names())
+}
+
+// run is the innermost handler, running the command itself
+func run(ctx context.Context, svc *Services, s *discordgo.Session, req *Request) error {
+	if req.Command.Handle != nil {
+		return req.Command.Handle(ctx, svc, s, req)
+	}
+
+	return req.Command.Run(ctx, svc, s, req.MsgParts, req.GuildID, req.AuthorID, req.ChannelID)
+}
+
+// Register adds a command to the default router
+func Register(cmd Command) error {
+	return DefaultRouter.Register(cmd)
+}
+
+// Lookup finds a command in the default router
+func Lookup(name string) (*Command, bool) {
+	return DefaultRouter.Lookup(name)
+}
+
+// Commands returns every command in the default router
+func Commands() []*Command {
+	return DefaultRouter.Commands()
+}
+
+// Names returns every name and alias in the default router
+func Names() []string {
+	return DefaultRouter.Names()
+}
+
+// ApplicationCommands returns the slash command definitions for the default router
+func ApplicationCommands() []*discordgo.ApplicationCommand {
+	return DefaultRouter.ApplicationCommands()
+}
+
+// Validate checks every command in the default router
+func Validate() []error {
+	return DefaultRouter.Validate()
+}
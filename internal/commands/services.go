@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/SonarBeserk/sophie-go/internal/db"
+	"github.com/SonarBeserk/sophie-go/internal/helpers"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Services holds everything commands depend on, passed to every command
+type Services struct {
+	// Settings stores locales, themes and opt outs
+	Settings db.SettingsStore
+	Stats    db.StatsStore
+	Members  Members
+	Catalog  *Catalog
+	Log      Logger
+	// Now returns the current time
+	Now func() time.Time
+}
+
+// NewServices returns services using Discord for members, stdout for logging and the system clock
+func NewServices(settings db.SettingsStore, stats db.StatsStore, catalog *Catalog) *Services {
+	return &Services{
+		Settings: settings,
+		Stats:    stats,
+		Members:  discordMembers{},
+		Catalog:  catalog,
+		Log:      stdoutLogger{},
+		Now:      time.Now,
+	}
+}
+
+// Logger writes log lines
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+// stdoutLogger writes log lines to stdout
+type stdoutLogger struct{}
+
+func (stdoutLogger) Printf(format string, args ...interface{}) {
+	fmt.Printf(format, args...)
+}
+
+// Members finds guild members
+type Members interface {
+	// Member gets a member by their id, returning nil if they aren't in the guild
+	Member(s *discordgo.Session, guildID string, userID string) (*discordgo.Member, error)
+	// FindMember gets a member by their username or nickname
+	FindMember(s *discordgo.Session, guildID string, name string) (*discordgo.Member, error)
+}
+
+// discordMembers finds members in the session's state, falling back to Discord's API
+type discordMembers struct{}
+
+func (discordMembers) Member(s *discordgo.Session, guildID string, userID string) (*discordgo.Member, error) {
+	if member, err := s.State.Member(guildID, userID); err == nil {
+		return member, nil
+	}
+
+	member, err := s.GuildMember(guildID, userID)
+	if err != nil {
+		if restErr, ok := err.(*discordgo.RESTError); ok && restErr.Response != nil && restErr.Response.StatusCode == 404 {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return member, nil
+}
+
+func (discordMembers) FindMember(s *discordgo.Session, guildID string, name string) (*discordgo.Member, error) {
+	return helpers.GetUserByName(s, guildID, name, true)
+}
@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"github.com/SonarBeserk/sophie-go/internal/db"
+	"github.com/SonarBeserk/sophie-go/internal/embed"
+	"github.com/bwmarrin/discordgo"
+)
+
+// previewCounts returns the counts an emote's footer shows once it has been recorded, without recording it.
+// The receiver's counts are shown when there is one, otherwise the sender's.
+func previewCounts(stats db.StatsStore, verb string, senderID string, receiverID string) (embed.EmoteCounts, error) {
+	if receiverID == "" {
+		sent, received, err := stats.GetEmoteCountsForUser(verb, senderID)
+		if err != nil {
+			return embed.EmoteCounts{}, err
+		}
+
+		return embed.EmoteCounts{Sent: sent + 1, Received: received}, nil
+	}
+
+	sent, received, err := stats.GetEmoteCountsForUser(verb, receiverID)
+	if err != nil {
+		return embed.EmoteCounts{}, err
+	}
+
+	return embed.EmoteCounts{Sent: sent, Received: received + 1}, nil
+}
+
+// statsReceiverID returns who is counted as receiving an emote, no one when it targets its sender so received stats aren't inflated
+func statsReceiverID(receiver *discordgo.Member, target embed.Target) string {
+	if receiver == nil || target == embed.TargetSelf {
+		return ""
+	}
+
+	return receiver.User.ID
+}
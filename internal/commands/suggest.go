@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/SonarBeserk/sophie-go/internal/i18n"
+	"github.com/bwmarrin/discordgo"
+)
+
+// HandleUnknownCommand suggests the closest known command when a user addresses the bot with one it doesn't know
+func HandleUnknownCommand(ctx context.Context, svc *Services, s *discordgo.Session, msgParts []string, guildID string, authorID string, channelID string, known []string) error {
+	if len(msgParts) < 1 {
+		return nil
+	}
+
+	suggestion, ok := Suggest(msgParts[0], known)
+	if !ok {
+		return nil
+	}
+
+	locale := resolveLocale(svc, s, guildID, authorID)
+
+	_, err := s.ChannelMessageSend(channelID, i18n.T(locale, i18n.MsgDidYouMean, msgParts[0], suggestion))
+	if err != nil {
+		return fmt.Errorf("error occurred sending message: %w", err)
+	}
+
+	return nil
+}
+
+// Suggest finds the known name closest to name by edit distance, if any is close enough to be a likely typo
+func Suggest(name string, known []string) (string, bool) {
+	name = strings.ToLower(name)
+
+	// Allow roughly one mistake for every three characters
+	maxDistance := len([]rune(name)) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+
+	// Sort so ties always suggest the same name
+	sorted := append([]string{}, known...)
+	sort.Strings(sorted)
+
+	best := ""
+	bestDistance := maxDistance + 1
+
+	for _, candidate := range sorted {
+		d := editDistance(name, strings.ToLower(candidate))
+		if d < bestDistance {
+			best = candidate
+			bestDistance = d
+		}
+	}
+
+	return best, best != ""
+}
+
+// editDistance calculates the edit distance between two strings, counting swapped neighbouring letters as one edit
+func editDistance(a string, b string) int {
+	ra := []rune(a)
+	rb := []rune(b)
+
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(ra)][len(rb)]
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,164 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SonarBeserk/sophie-go/internal/db"
+	"github.com/SonarBeserk/sophie-go/internal/embed"
+	"github.com/SonarBeserk/sophie-go/internal/emote"
+	"github.com/SonarBeserk/sophie-go/internal/i18n"
+	"github.com/bwmarrin/discordgo"
+)
+
+// HandleTheme shows or changes how emote embeds look in a guild
+func HandleTheme(ctx context.Context, svc *Services, s *discordgo.Session, msgParts []string, guildID string, authorID string, channelID string) error {
+	database := svc.Settings
+	locale := resolveLocale(svc, s, guildID, authorID)
+	args := msgParts[1:]
+
+	theme, err := database.GetGuildTheme(guildID)
+	if err != nil {
+		return fmt.Errorf("error occurred getting guild theme: %w", err)
+	}
+
+	reply := ""
+
+	if len(args) == 0 {
+		def := i18n.T(locale, i18n.MsgThemeDefault)
+		color, author, avatar := def, def, def
+
+		if theme.Color != nil {
+			color = fmt.Sprintf("#%06x", *theme.Color)
+		}
+
+		if theme.ShowAuthor != nil {
+			author = onOff(locale, *theme.ShowAuthor)
+		}
+
+		if theme.ShowReceiverAvatar != nil {
+			avatar = onOff(locale, *theme.ShowReceiverAvatar)
+		}
+
+		reply = i18n.T(locale, i18n.MsgThemeCurrent, color, author, avatar)
+		return sendReply(s, channelID, reply)
+	}
+
+	allowed, err := checkPermission(svc, s, guildID, authorID, channelID, discordgo.PermissionManageServer)
+	if err != nil || !allowed {
+		return err
+	}
+
+	value := ""
+	if len(args) > 1 {
+		value = foldName(args[1])
+	}
+
+	switch foldName(args[0]) {
+	case "reset":
+		theme = db.GuildTheme{}
+		reply = i18n.T(locale, i18n.MsgThemeReset)
+	case "color", "colour":
+		if value == "reset" {
+			theme.Color = nil
+			break
+		}
+
+		color, err := emote.ParseColor(value)
+		if err != nil {
+			return sendReply(s, channelID, i18n.T(locale, i18n.MsgThemeUsage))
+		}
+
+		theme.Color = &color
+	case "author":
+		show, ok := parseOnOff(value)
+		if !ok {
+			return sendReply(s, channelID, i18n.T(locale, i18n.MsgThemeUsage))
+		}
+
+		theme.ShowAuthor = show
+	case "avatar", "thumbnail":
+		show, ok := parseOnOff(value)
+		if !ok {
+			return sendReply(s, channelID, i18n.T(locale, i18n.MsgThemeUsage))
+		}
+
+		theme.ShowReceiverAvatar = show
+	default:
+		return sendReply(s, channelID, i18n.T(locale, i18n.MsgThemeUsage))
+	}
+
+	err = database.SetGuildTheme(guildID, theme)
+	if err != nil {
+		return fmt.Errorf("error occurred setting guild theme: %w", err)
+	}
+
+	if reply == "" {
+		reply = i18n.T(locale, i18n.MsgThemeUpdated)
+	}
+
+	return sendReply(s, channelID, reply)
+}
+
+// emoteStyle combines an emote's own style with the guild's theme overrides
+func emoteStyle(svc *Services, guildID string, em emote.Emote) embed.Style {
+	style := embed.Style{
+		Color:              em.EmbedColor(),
+		ShowAuthor:         em.ShowAuthor,
+		ShowReceiverAvatar: em.ShowReceiverAvatar,
+	}
+
+	theme, err := svc.Settings.GetGuildTheme(guildID)
+	if err != nil {
+		svc.Log.Printf("Error occurred getting theme for guild %s %v\n", guildID, err)
+		return style
+	}
+
+	if theme.Color != nil {
+		style.Color = theme.Color
+	}
+
+	if theme.ShowAuthor != nil {
+		style.ShowAuthor = *theme.ShowAuthor
+	}
+
+	if theme.ShowReceiverAvatar != nil {
+		style.ShowReceiverAvatar = *theme.ShowReceiverAvatar
+	}
+
+	return style
+}
+
+// parseOnOff parses on, off or reset, returning nil for reset
+func parseOnOff(value string) (*bool, bool) {
+	switch value {
+	case "on", "true", "yes":
+		b := true
+		return &b, true
+	case "off", "false", "no":
+		b := false
+		return &b, true
+	case "reset":
+		return nil, true
+	default:
+		return nil, false
+	}
+}
+
+// onOff names a setting's state in the reply's language
+func onOff(locale string, b bool) string {
+	if b {
+		return i18n.T(locale, i18n.MsgOn)
+	}
+
+	return i18n.T(locale, i18n.MsgOff)
+}
+
+func sendReply(s *discordgo.Session, channelID string, reply string) error {
+	_, err := s.ChannelMessageSend(channelID, reply)
+	if err != nil {
+		return fmt.Errorf("error occurred sending message: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Token is a word in a message and where it starts
+type Token struct {
+	Text string
+	// Start is the byte offset of the word in the message, including any opening quote
+	Start int
+}
+
+// Tokenize splits a message into words on any whitespace, shell style.
+// Quotes at the start of a word group words such as "Big Bob", and a backslash escapes the next character.
+// Quotes inside a word, such as in it's, and quotes that are never closed are kept as they are.
+func Tokenize(input string) []string {
+	return Words(Tokens(input))
+}
+
+// Words returns the text of each token
+func Words(tokens []Token) []string {
+	words := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		words = append(words, token.Text)
+	}
+
+	return words
+}
+
+// Tokens splits a message into words like Tokenize, keeping where each word starts
+func Tokens(input string) []Token {
+	runes := []rune(input)
+	tokens := []Token{}
+
+	// offsets maps rune indexes to byte offsets in input
+	offsets := make([]int, 0, len(runes))
+	for offset := range input {
+		offsets = append(offsets, offset)
+	}
+
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		if isQuote(runes[i]) {
+			if token, next, ok := quoted(runes, i); ok {
+				tokens = append(tokens, Token{Text: token, Start: offsets[i]})
+				i = next
+				continue
+			}
+		}
+
+		var sb strings.Builder
+		start := offsets[i]
+
+		for ; i < len(runes) && !unicode.IsSpace(runes[i]); i++ {
+			if runes[i] == '\\' && i+1 < len(runes) {
+				i++
+			}
+
+			sb.WriteRune(runes[i])
+		}
+
+		tokens = append(tokens, Token{Text: sb.String(), Start: start})
+	}
+
+	return tokens
+}
+
+// quoted reads a quoted word starting at start, returning false when the quote is never closed
+func quoted(runes []rune, start int) (string, int, bool) {
+	quote := closingQuote(runes[start])
+	var sb strings.Builder
+
+	for i := start + 1; i < len(runes); i++ {
+		switch {
+		case runes[i] == '\\' && i+1 < len(runes):
+			i++
+			sb.WriteRune(runes[i])
+		case runes[i] == quote:
+			return sb.String(), i + 1, true
+		default:
+			sb.WriteRune(runes[i])
+		}
+	}
+
+	return "", start, false
+}
+
+func isQuote(r rune) bool {
+	return r == '"' || r == '\'' || r == '“'
+}
+
+// closingQuote returns the quote that closes one, phones often type curly quotes
+func closingQuote(r rune) rune {
+	if r == '“' {
+		return '”'
+	}
+
+	return r
+}
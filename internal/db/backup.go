@@ -0,0 +1,120 @@
+package db
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	backupPrefix     = "sophie-"
+	backupSuffix     = ".db"
+	backupTimeFormat = "20060102-150405"
+)
+
+// Backup writes a consistent copy of the database to w while it stays open for reads and writes
+func (d Database) Backup(w io.Writer) (int64, error) {
+	var size int64
+
+	err := d.View(func(tx *bolt.Tx) error {
+		n, err := tx.WriteTo(w)
+		size = n
+		return err
+	})
+	if err != nil {
+		return size, errors.Wrap(err, "Error writing backup")
+	}
+
+	return size, nil
+}
+
+// BackupFile writes a copy of the database to path, only replacing path once the copy is complete
+func (d Database) BackupFile(path string) (int64, error) {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return 0, errors.Wrapf(err, "Error creating temporary file for %s", path)
+	}
+
+	size, err := d.Backup(tmp)
+	if err == nil {
+		err = tmp.Sync()
+	}
+
+	if closeErr := tmp.Close(); err == nil && closeErr != nil {
+		err = errors.Wrapf(closeErr, "Error closing temporary file for %s", path)
+	}
+
+	if err != nil {
+		os.Remove(tmp.Name())
+		return 0, err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return 0, errors.Wrapf(err, "Error replacing %s", path)
+	}
+
+	return size, nil
+}
+
+// BackupToDir writes a timestamped backup into dir and removes the oldest backups there so at most keep remain
+func (d Database) BackupToDir(dir string, keep int, now time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", errors.Wrapf(err, "Error creating backup directory %s", dir)
+	}
+
+	path := filepath.Join(dir, backupPrefix+now.UTC().Format(backupTimeFormat)+backupSuffix)
+
+	if _, err := d.BackupFile(path); err != nil {
+		return "", err
+	}
+
+	return path, RotateBackups(dir, keep)
+}
+
+// RotateBackups removes the oldest timestamped backups in dir so at most keep remain, keep of 0 or less keeps every backup
+func RotateBackups(dir string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return errors.Wrapf(err, "Error reading backup directory %s", dir)
+	}
+
+	backups := []string{}
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || !strings.HasPrefix(name, backupPrefix) || !strings.HasSuffix(name, backupSuffix) {
+			continue
+		}
+
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, backupPrefix), backupSuffix)
+		if _, err := time.Parse(backupTimeFormat, stamp); err != nil {
+			continue
+		}
+
+		backups = append(backups, name)
+	}
+
+	// Timestamps sort oldest first
+	sort.Strings(backups)
+
+	for len(backups) > keep {
+		if err := os.Remove(filepath.Join(dir, backups[0])); err != nil {
+			return errors.Wrapf(err, "Error removing old backup %s", backups[0])
+		}
+
+		backups = backups[1:]
+	}
+
+	return nil
+}
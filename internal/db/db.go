@@ -4,13 +4,20 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	bolt "go.etcd.io/bbolt"
 )
 
 var (
-	statsBucket string = "STATS"
+	// ErrDatabaseInUse is returned when a tool opens a database the bot has open
+	ErrDatabaseInUse = errors.New("it is in use, stop the bot first")
+
+	statsBucket    string = "STATS"
+	settingsBucket string = "SETTINGS"
+	// recordedBucket holds the keys of recorded emotes with when they were recorded
+	recordedBucket string = "RECORDED"
 )
 
 type Database struct {
@@ -20,18 +27,39 @@ type Database struct {
 func OpenOrConfigureDatabase(databaseFile string) (*Database, error) {
 	db, err := bolt.Open(databaseFile, 0666, nil)
 	if err != nil {
-		return nil, errors.Wrapf(err, "Error loading database file %s: %v", databaseFile)
+		return nil, errors.Wrapf(err, "Error loading database file %s", databaseFile)
 	}
 
-	err = db.Update(func(tx *bolt.Tx) error {
-		_, err := tx.CreateBucketIfNotExists([]byte(statsBucket))
-		if err != nil {
-			return errors.Wrapf(err, "Could not create root bucket: %v")
-		}
-		return nil
-	})
+	applied, err := migrate(db, false)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	for _, m := range applied {
+		fmt.Printf("Migrated database to schema version %d: %s\n", m.Version, m.Description)
+	}
+
+	return &Database{
+		DB: db,
+	}, nil
+}
+
+// OpenDatabaseForTool opens a database for command line tools, failing quickly instead of waiting while the bot has it open
+func OpenDatabaseForTool(databaseFile string, readOnly bool) (*Database, error) {
+	db, err := bolt.Open(databaseFile, 0666, &bolt.Options{Timeout: time.Second, ReadOnly: readOnly})
+	if err == bolt.ErrTimeout {
+		return nil, errors.Wrapf(ErrDatabaseInUse, "Error loading database file %s", databaseFile)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error loading database file %s", databaseFile)
+	}
+
+	// Tools leave migrating to the bot or the migrate command, but must not read newer databases
+	err = checkSchemaVersion(db)
 	if err != nil {
-		return nil, errors.Wrapf(err, "Could not set up buckets, %v")
+		db.Close()
+		return nil, err
 	}
 
 	return &Database{
@@ -111,26 +139,245 @@ func (d Database) SetEmoteReceivedUsage(emote string, userID string, count int)
 	return err
 }
 
+// GetEmoteCountsForUser returns how many times a user has sent and received an emote
 func (d Database) GetEmoteCountsForUser(emote string, userID string) (sent int, received int, err error) {
 	sentCount, err := d.GetEmoteSentUsage(emote, userID)
 	if err != nil {
 		return 0, 0, err
 	}
 
-	err = d.SetEmoteSentUsage(emote, userID, sentCount)
+	receivedCount, err := d.GetEmoteReceivedUsage(emote, userID)
 	if err != nil {
 		return 0, 0, err
 	}
 
-	receivedCount, err := d.GetEmoteReceivedUsage(emote, userID)
-	if err != nil {
-		return 0, 0, err
+	return sentCount, receivedCount, nil
+}
+
+// AddEmoteSentUsage adds to how many times a user has sent an emote, returning the new count
+func (d Database) AddEmoteSentUsage(emote string, userID string, delta int) (int, error) {
+	return d.addUsage(strings.ToUpper(emote)+"|"+strings.ToUpper(userID)+"|Sent", delta)
+}
+
+// AddEmoteReceivedUsage adds to how many times a user has received an emote, returning the new count
+func (d Database) AddEmoteReceivedUsage(emote string, userID string, delta int) (int, error) {
+	return d.addUsage(strings.ToUpper(emote)+"|"+strings.ToUpper(userID)+"|Received", delta)
+}
+
+// RecordEmote counts an emote sent by a user and, unless receiverID is empty, received by another in one transaction.
+// A non-empty key is remembered, returning false without counting again if it has already been recorded.
+func (d Database) RecordEmote(key string, emote string, senderID string, receiverID string) (bool, error) {
+	recorded := false
+
+	err := d.Update(func(tx *bolt.Tx) error {
+		if key != "" {
+			bucket := tx.Bucket([]byte(recordedBucket))
+			if bucket.Get([]byte(key)) != nil {
+				return nil
+			}
+
+			err := bucket.Put([]byte(key), []byte(strconv.FormatInt(time.Now().Unix(), 10)))
+			if err != nil {
+				return fmt.Errorf("Could not insert recorded key: %v", err)
+			}
+		}
+
+		_, err := addUsageTx(tx, strings.ToUpper(emote)+"|"+strings.ToUpper(senderID)+"|Sent", 1)
+		if err != nil {
+			return err
+		}
+
+		if receiverID != "" {
+			_, err = addUsageTx(tx, strings.ToUpper(emote)+"|"+strings.ToUpper(receiverID)+"|Received", 1)
+			if err != nil {
+				return err
+			}
+		}
+
+		recorded = true
+		return nil
+	})
+
+	return recorded, err
+}
+
+// PruneRecorded forgets keys recorded before a time, returning how many were removed
+func (d Database) PruneRecorded(before time.Time) (int, error) {
+	pruned := 0
+
+	err := d.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(recordedBucket))
+
+		// Keys can't be deleted while iterating over the bucket
+		old := [][]byte{}
+		err := bucket.ForEach(func(k, v []byte) error {
+			recordedAt, err := strconv.ParseInt(string(v), 10, 64)
+			if err != nil || recordedAt < before.Unix() {
+				old = append(old, append([]byte{}, k...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, k := range old {
+			if err := bucket.Delete(k); err != nil {
+				return fmt.Errorf("Could not delete recorded key: %v", err)
+			}
+		}
+
+		pruned = len(old)
+		return nil
+	})
+
+	return pruned, err
+}
+
+// addUsage reads and updates a count in a single transaction so concurrent commands can't lose updates
+func (d Database) addUsage(key string, delta int) (int, error) {
+	count := 0
+
+	err := d.Update(func(tx *bolt.Tx) error {
+		var err error
+		count, err = addUsageTx(tx, key, delta)
+		return err
+	})
+
+	return count, err
+}
+
+// addUsageTx updates a count within a transaction, returning the new count
+func addUsageTx(tx *bolt.Tx, key string, delta int) (int, error) {
+	bucket := tx.Bucket([]byte(statsBucket))
+	count := 0
+
+	if val := bucket.Get([]byte(key)); val != nil {
+		curCount, err := strconv.Atoi(string(val))
+		if err != nil {
+			return 0, err
+		}
+
+		count = curCount
 	}
 
-	err = d.SetEmoteSentUsage(emote, userID, receivedCount)
+	count += delta
+
+	err := bucket.Put([]byte(key), []byte(strconv.Itoa(count)))
 	if err != nil {
-		return 0, 0, err
+		return 0, fmt.Errorf("Could not insert stat: %v", err)
 	}
 
-	return sentCount, receivedCount, nil
+	return count, nil
+}
+
+// GetUserEmoteCounts returns a user's counts for every emote they have sent or received, keyed by lowercase verb
+func (d Database) GetUserEmoteCounts(userID string) (map[string]EmoteCounts, error) {
+	counts := map[string]EmoteCounts{}
+	userID = strings.ToUpper(userID)
+
+	err := d.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(statsBucket)).ForEach(func(k, v []byte) error {
+			parts := strings.Split(string(k), "|")
+			if len(parts) != 3 || parts[1] != userID {
+				return nil
+			}
+
+			count, err := strconv.Atoi(string(v))
+			if err != nil {
+				return err
+			}
+
+			verb := strings.ToLower(parts[0])
+			c := counts[verb]
+			switch parts[2] {
+			case "Sent":
+				c.Sent = count
+			case "Received":
+				c.Received = count
+			}
+			counts[verb] = c
+
+			return nil
+		})
+	})
+
+	return counts, err
+}
+
+func (d Database) getSetting(scope string, id string, name string) (string, error) {
+	value := ""
+
+	err := d.View(func(tx *bolt.Tx) error {
+		val := tx.Bucket([]byte(settingsBucket)).Get([]byte(settingKey(scope, id, name)))
+		if val != nil {
+			value = string(val)
+		}
+		return nil
+	})
+
+	return value, err
+}
+
+func (d Database) setSetting(scope string, id string, name string, value string) error {
+	err := d.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(settingsBucket))
+		key := []byte(settingKey(scope, id, name))
+
+		if value == "" {
+			return bucket.Delete(key)
+		}
+
+		err := bucket.Put(key, []byte(value))
+		if err != nil {
+			return fmt.Errorf("Could not insert setting: %v", err)
+		}
+		return nil
+	})
+	return err
+}
+
+// settingKey is the bolt key a setting is stored under
+func settingKey(scope string, id string, name string) string {
+	return scope + "|" + strings.ToUpper(id) + "|" + name
+}
+
+// GetGuildLocale returns the locale set for a guild, or an empty string if none has been set
+func (d Database) GetGuildLocale(guildID string) (string, error) {
+	return getGuildLocale(d, guildID)
+}
+
+// SetGuildLocale sets the locale for a guild, an empty locale clears it
+func (d Database) SetGuildLocale(guildID string, locale string) error {
+	return setGuildLocale(d, guildID, locale)
+}
+
+// GetUserLocale returns the locale a user has chosen, or an empty string if none has been set
+func (d Database) GetUserLocale(userID string) (string, error) {
+	return getUserLocale(d, userID)
+}
+
+// SetUserLocale sets the locale for a user, an empty locale clears it
+func (d Database) SetUserLocale(userID string, locale string) error {
+	return setUserLocale(d, userID, locale)
+}
+
+// GetGuildTheme returns the theme overrides for a guild
+func (d Database) GetGuildTheme(guildID string) (GuildTheme, error) {
+	return getGuildTheme(d, guildID)
+}
+
+// SetGuildTheme sets the theme overrides for a guild
+func (d Database) SetGuildTheme(guildID string, theme GuildTheme) error {
+	return setGuildTheme(d, guildID, theme)
+}
+
+// GetUserOptOut checks if a user has opted out of being the target of emotes
+func (d Database) GetUserOptOut(userID string) (bool, error) {
+	return getUserOptOut(d, userID)
+}
+
+// SetUserOptOut sets whether a user has opted out of being the target of emotes
+func (d Database) SetUserOptOut(userID string, optOut bool) error {
+	return setUserOptOut(d, userID, optOut)
 }
@@ -0,0 +1,266 @@
+package db
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// exportVersion is increased when the export format changes
+const exportVersion = 1
+
+var (
+	csvHeader []string = []string{"kind", "scope", "id", "name", "sent", "received", "value"}
+)
+
+// Export holds everything in the database in a form other tools can read
+type Export struct {
+	Version  int             `json:"version"`
+	Stats    []StatRecord    `json:"stats"`
+	Settings []SettingRecord `json:"settings"`
+}
+
+// StatRecord holds how many times a user has sent and received an emote
+type StatRecord struct {
+	Emote    string `json:"emote"`
+	UserID   string `json:"userId"`
+	Sent     int    `json:"sent"`
+	Received int    `json:"received"`
+}
+
+// SettingRecord holds a guild or user setting such as a locale, theme or opt out
+type SettingRecord struct {
+	// Scope is GUILD or USER
+	Scope string `json:"scope"`
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Export reads every stat and setting in the database
+func (d Database) Export() (*Export, error) {
+	e := &Export{
+		Version:  exportVersion,
+		Stats:    []StatRecord{},
+		Settings: []SettingRecord{},
+	}
+
+	err := d.View(func(tx *bolt.Tx) error {
+		stats := map[string]int{}
+
+		if bucket := tx.Bucket([]byte(statsBucket)); bucket != nil {
+			err := bucket.ForEach(func(k, v []byte) error {
+				parts := strings.Split(string(k), "|")
+				if len(parts) != 3 {
+					return errors.Errorf("Unexpected stats key %q", k)
+				}
+
+				count, err := strconv.Atoi(string(v))
+				if err != nil {
+					return errors.Wrapf(err, "Error parsing stats value for %q", k)
+				}
+
+				// Sent and received counts are stored separately but exported together
+				key := parts[0] + "|" + parts[1]
+				i, ok := stats[key]
+				if !ok {
+					i = len(e.Stats)
+					stats[key] = i
+					e.Stats = append(e.Stats, StatRecord{Emote: parts[0], UserID: parts[1]})
+				}
+
+				switch parts[2] {
+				case "Sent":
+					e.Stats[i].Sent = count
+				case "Received":
+					e.Stats[i].Received = count
+				default:
+					return errors.Errorf("Unexpected stats key %q", k)
+				}
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if bucket := tx.Bucket([]byte(settingsBucket)); bucket != nil {
+			err := bucket.ForEach(func(k, v []byte) error {
+				parts := strings.SplitN(string(k), "|", 3)
+				if len(parts) != 3 {
+					return errors.Errorf("Unexpected settings key %q", k)
+				}
+
+				e.Settings = append(e.Settings, SettingRecord{Scope: parts[0], ID: parts[1], Name: parts[2], Value: string(v)})
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error exporting database")
+	}
+
+	return e, nil
+}
+
+// Import writes every stat and setting in an export in a single transaction.
+// Existing values are overwritten, and when replace is set everything else is removed first.
+func (d Database) Import(e *Export, replace bool) error {
+	if err := checkExport(e); err != nil {
+		return err
+	}
+
+	err := d.Update(func(tx *bolt.Tx) error {
+		if replace {
+			for _, name := range []string{statsBucket, settingsBucket} {
+				if err := tx.DeleteBucket([]byte(name)); err != nil && err != bolt.ErrBucketNotFound {
+					return errors.Wrapf(err, "Error clearing bucket %s", name)
+				}
+			}
+		}
+
+		stats, err := tx.CreateBucketIfNotExists([]byte(statsBucket))
+		if err != nil {
+			return err
+		}
+
+		settings, err := tx.CreateBucketIfNotExists([]byte(settingsBucket))
+		if err != nil {
+			return err
+		}
+
+		for _, s := range e.Stats {
+			key := strings.ToUpper(s.Emote) + "|" + strings.ToUpper(s.UserID)
+
+			if err := stats.Put([]byte(key+"|Sent"), []byte(strconv.Itoa(s.Sent))); err != nil {
+				return err
+			}
+
+			if err := stats.Put([]byte(key+"|Received"), []byte(strconv.Itoa(s.Received))); err != nil {
+				return err
+			}
+		}
+
+		for _, s := range e.Settings {
+			if err := settings.Put([]byte(settingKey(s.Scope, s.ID, s.Name)), []byte(s.Value)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "Error importing database")
+	}
+
+	return nil
+}
+
+// checkExport checks an export can be imported before anything is written
+func checkExport(e *Export) error {
+	if e.Version != exportVersion {
+		return errors.Errorf("Unsupported export version %d", e.Version)
+	}
+
+	for _, s := range e.Settings {
+		if s.Scope != ScopeGuild && s.Scope != ScopeUser {
+			return errors.Errorf("Unexpected setting scope %q for %s", s.Scope, s.ID)
+		}
+	}
+
+	return nil
+}
+
+// WriteJSON writes an export as indented JSON
+func (e *Export) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(e); err != nil {
+		return errors.Wrap(err, "Error encoding export")
+	}
+
+	return nil
+}
+
+// WriteCSV writes an export as CSV with one row for each stat or setting
+func (e *Export) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	rows := [][]string{csvHeader}
+	for _, s := range e.Stats {
+		rows = append(rows, []string{"stat", "", s.UserID, s.Emote, strconv.Itoa(s.Sent), strconv.Itoa(s.Received), ""})
+	}
+
+	for _, s := range e.Settings {
+		rows = append(rows, []string{"setting", s.Scope, s.ID, s.Name, "", "", s.Value})
+	}
+
+	if err := cw.WriteAll(rows); err != nil {
+		return errors.Wrap(err, "Error encoding export")
+	}
+
+	return nil
+}
+
+// ReadExportJSON reads an export written by WriteJSON
+func ReadExportJSON(r io.Reader) (*Export, error) {
+	e := &Export{}
+
+	if err := json.NewDecoder(r).Decode(e); err != nil {
+		return nil, errors.Wrap(err, "Error parsing export")
+	}
+
+	return e, nil
+}
+
+// ReadExportCSV reads an export written by WriteCSV
+func ReadExportCSV(r io.Reader) (*Export, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(csvHeader)
+
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing export")
+	}
+
+	if len(rows) == 0 || strings.Join(rows[0], ",") != strings.Join(csvHeader, ",") {
+		return nil, errors.New("Error parsing export: missing header")
+	}
+
+	e := &Export{Version: exportVersion}
+
+	for i, row := range rows[1:] {
+		switch row[0] {
+		case "stat":
+			sent, err := strconv.Atoi(row[4])
+			if err != nil {
+				return nil, errors.Wrapf(err, "Error parsing sent count on line %d", i+2)
+			}
+
+			received, err := strconv.Atoi(row[5])
+			if err != nil {
+				return nil, errors.Wrapf(err, "Error parsing received count on line %d", i+2)
+			}
+
+			e.Stats = append(e.Stats, StatRecord{Emote: row[3], UserID: row[2], Sent: sent, Received: received})
+		case "setting":
+			e.Settings = append(e.Settings, SettingRecord{Scope: row[1], ID: row[2], Name: row[3], Value: row[6]})
+		default:
+			return nil, errors.Errorf("Error parsing export: unknown kind %q on line %d", row[0], i+2)
+		}
+	}
+
+	return e, nil
+}
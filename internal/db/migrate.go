@@ -0,0 +1,167 @@
+package db
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	metaBucket       string = "META"
+	schemaVersionKey string = "SchemaVersion"
+
+	// errDryRun rolls back the transaction a dry run migrates in
+	errDryRun = errors.New("dry run")
+
+	// migrations upgrade the database one schema version at a time, in order.
+	// Never change a migration that has been released, add a new one instead.
+	migrations []Migration = []Migration{
+		{
+			Version:     1,
+			Description: "Create the stats and settings buckets",
+			Up: func(tx *bolt.Tx) error {
+				for _, name := range []string{statsBucket, settingsBucket} {
+					if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+						return errors.Wrapf(err, "Could not create bucket %s", name)
+					}
+				}
+
+				return nil
+			},
+		},
+		{
+			Version:     2,
+			Description: "Create the recorded emotes bucket",
+			Up: func(tx *bolt.Tx) error {
+				if _, err := tx.CreateBucketIfNotExists([]byte(recordedBucket)); err != nil {
+					return errors.Wrapf(err, "Could not create bucket %s", recordedBucket)
+				}
+
+				return nil
+			},
+		},
+	}
+)
+
+// Migration upgrades the database from the previous schema version to Version
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(tx *bolt.Tx) error
+}
+
+// LatestSchemaVersion is the schema version this build reads and writes
+func LatestSchemaVersion() int {
+	return migrations[len(migrations)-1].Version
+}
+
+// SchemaVersion returns the database's schema version, 0 for databases from before versions were recorded
+func (d Database) SchemaVersion() (int, error) {
+	version := 0
+
+	err := d.View(func(tx *bolt.Tx) error {
+		v, err := schemaVersion(tx)
+		version = v
+		return err
+	})
+
+	return version, err
+}
+
+// Migrate applies every pending migration in a single transaction, so either all of them are applied or none are.
+// With dryRun set the migrations are run and then rolled back, reporting what would be applied.
+func (d Database) Migrate(dryRun bool) ([]Migration, error) {
+	return migrate(d.DB, dryRun)
+}
+
+func migrate(db *bolt.DB, dryRun bool) ([]Migration, error) {
+	applied := []Migration{}
+
+	err := db.Update(func(tx *bolt.Tx) error {
+		version, err := schemaVersion(tx)
+		if err != nil {
+			return err
+		}
+
+		if version > LatestSchemaVersion() {
+			return errors.Errorf("Database schema version %d is newer than this build supports (%d)", version, LatestSchemaVersion())
+		}
+
+		for _, m := range migrations {
+			if m.Version <= version {
+				continue
+			}
+
+			if err := m.Up(tx); err != nil {
+				return errors.Wrapf(err, "Error migrating to schema version %d", m.Version)
+			}
+
+			applied = append(applied, m)
+			version = m.Version
+		}
+
+		if len(applied) == 0 {
+			return nil
+		}
+
+		meta, err := tx.CreateBucketIfNotExists([]byte(metaBucket))
+		if err != nil {
+			return errors.Wrap(err, "Could not create meta bucket")
+		}
+
+		err = meta.Put([]byte(schemaVersionKey), []byte(strconv.Itoa(version)))
+		if err != nil {
+			return errors.Wrap(err, "Could not record schema version")
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+
+		return nil
+	})
+	if err == errDryRun {
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+// checkSchemaVersion stops builds from reading databases written by newer ones
+func checkSchemaVersion(db *bolt.DB) error {
+	return db.View(func(tx *bolt.Tx) error {
+		version, err := schemaVersion(tx)
+		if err != nil {
+			return err
+		}
+
+		if version > LatestSchemaVersion() {
+			return errors.Errorf("Database schema version %d is newer than this build supports (%d)", version, LatestSchemaVersion())
+		}
+
+		return nil
+	})
+}
+
+func schemaVersion(tx *bolt.Tx) (int, error) {
+	meta := tx.Bucket([]byte(metaBucket))
+	if meta == nil {
+		return 0, nil
+	}
+
+	val := meta.Get([]byte(schemaVersionKey))
+	if val == nil {
+		return 0, nil
+	}
+
+	version, err := strconv.Atoi(string(val))
+	if err != nil {
+		return 0, errors.Wrapf(err, "Could not parse schema version %q", val)
+	}
+
+	return version, nil
+}
@@ -0,0 +1,174 @@
+package db
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// fixture describes a database to migrate, buckets map bucket names to their keys and values
+type fixture struct {
+	version int
+	buckets map[string]map[string]string
+}
+
+// v0Fixture is a database from before schema versions were recorded, holding only stats
+var v0Fixture fixture = fixture{
+	buckets: map[string]map[string]string{
+		statsBucket: {"HUG|1|Sent": "3", "HUG|2|Received": "3"},
+	},
+}
+
+// writeFixture creates a bolt database from a fixture, recording its schema version unless it is 0
+func writeFixture(t *testing.T, f fixture) string {
+	dir, err := ioutil.TempDir("", "sophie-migrate")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "data.db")
+
+	db, err := bolt.Open(path, 0666, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if f.version != 0 {
+			f.buckets[metaBucket] = map[string]string{schemaVersionKey: strconv.Itoa(f.version)}
+		}
+
+		for name, values := range f.buckets {
+			bucket, err := tx.CreateBucket([]byte(name))
+			if err != nil {
+				return err
+			}
+
+			for k, v := range values {
+				if err := bucket.Put([]byte(k), []byte(v)); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// bucketNames lists the buckets in a database
+func bucketNames(t *testing.T, d *Database) map[string]bool {
+	names := map[string]bool{}
+
+	err := d.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			names[string(name)] = true
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return names
+}
+
+func TestMigrateFromV0(t *testing.T) {
+	path := writeFixture(t, v0Fixture)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	d, err := OpenOrConfigureDatabase(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	if version, err := d.SchemaVersion(); err != nil || version != LatestSchemaVersion() {
+		t.Fatalf("SchemaVersion = %d, %v, want %d", version, err, LatestSchemaVersion())
+	}
+
+	names := bucketNames(t, d)
+	for _, name := range []string{metaBucket, statsBucket, settingsBucket, recordedBucket} {
+		if !names[name] {
+			t.Errorf("bucket %s is missing after migrating", name)
+		}
+	}
+
+	if sent, received, err := d.GetEmoteCountsForUser("hug", "1"); err != nil || sent != 3 || received != 0 {
+		t.Errorf("GetEmoteCountsForUser = %d, %d, %v, want the stats from before migrating", sent, received, err)
+	}
+
+	// Every migration has been applied so running them again does nothing
+	applied, err := d.Migrate(false)
+	if err != nil || len(applied) != 0 {
+		t.Fatalf("Migrate again = %v, %v, want nothing applied", applied, err)
+	}
+
+	if version, err := d.SchemaVersion(); err != nil || version != LatestSchemaVersion() {
+		t.Fatalf("SchemaVersion after migrating again = %d, %v, want %d", version, err, LatestSchemaVersion())
+	}
+}
+
+func TestMigrateDryRun(t *testing.T) {
+	path := writeFixture(t, v0Fixture)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	d, err := OpenDatabaseForTool(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	applied, err := d.Migrate(true)
+	if err != nil || len(applied) != len(migrations) {
+		t.Fatalf("Migrate dry run = %d migrations, %v, want %d", len(applied), err, len(migrations))
+	}
+
+	if version, err := d.SchemaVersion(); err != nil || version != 0 {
+		t.Fatalf("SchemaVersion after a dry run = %d, %v, want 0", version, err)
+	}
+
+	names := bucketNames(t, d)
+	if len(names) != 1 || !names[statsBucket] {
+		t.Fatalf("buckets after a dry run = %v, want only %s", names, statsBucket)
+	}
+}
+
+func TestMigrateRefusesNewerVersion(t *testing.T) {
+	newer := LatestSchemaVersion() + 1
+	path := writeFixture(t, fixture{
+		version: newer,
+		buckets: map[string]map[string]string{statsBucket: {}},
+	})
+	defer os.RemoveAll(filepath.Dir(path))
+
+	if d, err := OpenOrConfigureDatabase(path); err == nil {
+		d.Close()
+		t.Fatal("OpenOrConfigureDatabase opened a database newer than this build")
+	}
+
+	if d, err := OpenDatabaseForTool(path, true); err == nil {
+		d.Close()
+		t.Fatal("OpenDatabaseForTool opened a database newer than this build")
+	}
+
+	db, err := bolt.Open(path, 0666, &bolt.Options{ReadOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	d := &Database{DB: db}
+	if version, err := d.SchemaVersion(); err != nil || version != newer {
+		t.Fatalf("SchemaVersion after refusing = %d, %v, want it left at %d", version, err, newer)
+	}
+}
@@ -0,0 +1,17 @@
+package db
+
+import (
+	"strconv"
+
+	// Registers the postgres driver
+	_ "github.com/lib/pq"
+)
+
+var (
+	postgresDialect sqlDialect = sqlDialect{
+		driver: "postgres",
+		placeholder: func(n int) string {
+			return "$" + strconv.Itoa(n)
+		},
+	}
+)
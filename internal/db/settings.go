@@ -0,0 +1,106 @@
+package db
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Setting scopes
+const (
+	ScopeGuild = "GUILD"
+	ScopeUser  = "USER"
+)
+
+// SettingsStore stores guild and user settings
+type SettingsStore interface {
+	// GetGuildLocale returns the locale set for a guild, or an empty string if none has been set
+	GetGuildLocale(guildID string) (string, error)
+	// SetGuildLocale sets the locale for a guild, an empty locale clears it
+	SetGuildLocale(guildID string, locale string) error
+	// GetUserLocale returns the locale a user has chosen, or an empty string if none has been set
+	GetUserLocale(userID string) (string, error)
+	// SetUserLocale sets the locale for a user, an empty locale clears it
+	SetUserLocale(userID string, locale string) error
+	// GetGuildTheme returns the theme overrides for a guild
+	GetGuildTheme(guildID string) (GuildTheme, error)
+	// SetGuildTheme sets the theme overrides for a guild
+	SetGuildTheme(guildID string, theme GuildTheme) error
+	// GetUserOptOut checks if a user has opted out of being the target of emotes
+	GetUserOptOut(userID string) (bool, error)
+	// SetUserOptOut sets whether a user has opted out of being the target of emotes
+	SetUserOptOut(userID string, optOut bool) error
+}
+
+// settingValues reads and writes raw setting values, every store keeps settings the same way on top of them
+type settingValues interface {
+	getSetting(scope string, id string, name string) (string, error)
+	// setSetting stores a setting, an empty value removes it
+	setSetting(scope string, id string, name string, value string) error
+}
+
+// GuildTheme overrides how emote embeds look in a guild, unset values use each emote's own style
+type GuildTheme struct {
+	Color              *int  `json:"color,omitempty"`
+	ShowAuthor         *bool `json:"showAuthor,omitempty"`
+	ShowReceiverAvatar *bool `json:"showReceiverAvatar,omitempty"`
+}
+
+func getGuildLocale(v settingValues, guildID string) (string, error) {
+	return v.getSetting(ScopeGuild, guildID, "Locale")
+}
+
+func setGuildLocale(v settingValues, guildID string, locale string) error {
+	return v.setSetting(ScopeGuild, guildID, "Locale", locale)
+}
+
+func getUserLocale(v settingValues, userID string) (string, error) {
+	return v.getSetting(ScopeUser, userID, "Locale")
+}
+
+func setUserLocale(v settingValues, userID string, locale string) error {
+	return v.setSetting(ScopeUser, userID, "Locale", locale)
+}
+
+func getGuildTheme(v settingValues, guildID string) (GuildTheme, error) {
+	theme := GuildTheme{}
+
+	val, err := v.getSetting(ScopeGuild, guildID, "Theme")
+	if err != nil || val == "" {
+		return theme, err
+	}
+
+	err = json.Unmarshal([]byte(val), &theme)
+	if err != nil {
+		return theme, errors.Wrapf(err, "Could not parse theme for guild %s", guildID)
+	}
+
+	return theme, nil
+}
+
+func setGuildTheme(v settingValues, guildID string, theme GuildTheme) error {
+	if theme == (GuildTheme{}) {
+		return v.setSetting(ScopeGuild, guildID, "Theme", "")
+	}
+
+	val, err := json.Marshal(theme)
+	if err != nil {
+		return errors.Wrapf(err, "Could not encode theme for guild %s", guildID)
+	}
+
+	return v.setSetting(ScopeGuild, guildID, "Theme", string(val))
+}
+
+func getUserOptOut(v settingValues, userID string) (bool, error) {
+	val, err := v.getSetting(ScopeUser, userID, "OptOut")
+	return val == "true", err
+}
+
+func setUserOptOut(v settingValues, userID string, optOut bool) error {
+	val := ""
+	if optOut {
+		val = "true"
+	}
+
+	return v.setSetting(ScopeUser, userID, "OptOut", val)
+}
@@ -0,0 +1,368 @@
+package db
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// sqlDialect holds the differences between the sql databases stats and settings can be stored in
+type sqlDialect struct {
+	// driver is the database/sql driver name
+	driver string
+	// placeholder returns the parameter placeholder for the nth argument, starting from 1
+	placeholder func(n int) string
+}
+
+// sqlStore stores stats and settings in a sql database, letting several bot instances and other tools use them at once
+type sqlStore struct {
+	db      *sql.DB
+	dialect sqlDialect
+}
+
+const createStatsTable = `CREATE TABLE IF NOT EXISTS emote_stats (
+	emote TEXT NOT NULL,
+	user_id TEXT NOT NULL,
+	sent INTEGER NOT NULL DEFAULT 0,
+	received INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (emote, user_id)
+)`
+
+const createRecordedTable = `CREATE TABLE IF NOT EXISTS emote_records (
+	record_key TEXT NOT NULL PRIMARY KEY,
+	recorded_at BIGINT NOT NULL
+)`
+
+const createRecordedIndex = `CREATE INDEX IF NOT EXISTS emote_records_recorded_at ON emote_records (recorded_at)`
+
+const createSettingsTable = `CREATE TABLE IF NOT EXISTS settings (
+	scope TEXT NOT NULL,
+	id TEXT NOT NULL,
+	name TEXT NOT NULL,
+	value TEXT NOT NULL,
+	PRIMARY KEY (scope, id, name)
+)`
+
+// queryRower runs a query returning a single row, on the database or within a transaction
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// openSQLStore connects to a sql database and creates the tables if they don't exist
+func openSQLStore(dialect sqlDialect, dsn string) (*sqlStore, error) {
+	if dsn == "" {
+		return nil, errors.Errorf("A connection string is needed for the %s store", dialect.driver)
+	}
+
+	db, err := sql.Open(dialect.driver, dsn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error opening the %s store", dialect.driver)
+	}
+
+	for _, create := range []string{createStatsTable, createRecordedTable, createRecordedIndex, createSettingsTable} {
+		if _, err := db.Exec(create); err != nil {
+			db.Close()
+			return nil, errors.Wrapf(err, "Error creating %s tables", dialect.driver)
+		}
+	}
+
+	return &sqlStore{db: db, dialect: dialect}, nil
+}
+
+// query replaces ? placeholders with the dialect's own
+func (s *sqlStore) query(q string) string {
+	var sb strings.Builder
+	n := 0
+
+	for _, r := range q {
+		if r == '?' {
+			n++
+			sb.WriteString(s.dialect.placeholder(n))
+			continue
+		}
+
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+func (s *sqlStore) GetEmoteCountsForUser(emote string, userID string) (int, int, error) {
+	sent, received := 0, 0
+
+	err := s.db.QueryRow(s.query(`SELECT sent, received FROM emote_stats WHERE emote = ? AND user_id = ?`),
+		strings.ToUpper(emote), strings.ToUpper(userID)).Scan(&sent, &received)
+	if err == sql.ErrNoRows {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "Error getting %s stats for %s", emote, userID)
+	}
+
+	return sent, received, nil
+}
+
+func (s *sqlStore) GetUserEmoteCounts(userID string) (map[string]EmoteCounts, error) {
+	rows, err := s.db.Query(s.query(`SELECT emote, sent, received FROM emote_stats WHERE user_id = ?`), strings.ToUpper(userID))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error getting stats for %s", userID)
+	}
+	defer rows.Close()
+
+	counts := map[string]EmoteCounts{}
+
+	for rows.Next() {
+		var emote string
+		var c EmoteCounts
+
+		if err := rows.Scan(&emote, &c.Sent, &c.Received); err != nil {
+			return nil, errors.Wrapf(err, "Error reading stats for %s", userID)
+		}
+
+		counts[strings.ToLower(emote)] = c
+	}
+
+	return counts, rows.Err()
+}
+
+func (s *sqlStore) AddEmoteSentUsage(emote string, userID string, delta int) (int, error) {
+	return s.add(s.db, "sent", emote, userID, delta)
+}
+
+func (s *sqlStore) AddEmoteReceivedUsage(emote string, userID string, delta int) (int, error) {
+	return s.add(s.db, "received", emote, userID, delta)
+}
+
+func (s *sqlStore) RecordEmote(key string, emote string, senderID string, receiverID string) (bool, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, errors.Wrapf(err, "Error recording %s", emote)
+	}
+	defer tx.Rollback()
+
+	if key != "" {
+		res, err := tx.Exec(s.query(`INSERT INTO emote_records (record_key, recorded_at) VALUES (?, ?) ON CONFLICT (record_key) DO NOTHING`),
+			key, time.Now().Unix())
+		if err != nil {
+			return false, errors.Wrapf(err, "Error recording %s key %s", emote, key)
+		}
+
+		inserted, err := res.RowsAffected()
+		if err != nil {
+			return false, errors.Wrapf(err, "Error recording %s key %s", emote, key)
+		}
+
+		// The key was recorded before so the emote has already been counted
+		if inserted == 0 {
+			return false, nil
+		}
+	}
+
+	if _, err := s.add(tx, "sent", emote, senderID, 1); err != nil {
+		return false, err
+	}
+
+	if receiverID != "" {
+		if _, err := s.add(tx, "received", emote, receiverID, 1); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, errors.Wrapf(err, "Error recording %s", emote)
+	}
+
+	return true, nil
+}
+
+// add increments a count with a single upsert so concurrent instances can't lose updates
+func (s *sqlStore) add(q queryRower, column string, emote string, userID string, delta int) (int, error) {
+	upsert := `INSERT INTO emote_stats (emote, user_id, ` + column + `) VALUES (?, ?, ?)
+		ON CONFLICT (emote, user_id) DO UPDATE SET ` + column + ` = emote_stats.` + column + ` + excluded.` + column + `
+		RETURNING ` + column
+
+	count := 0
+
+	err := q.QueryRow(s.query(upsert), strings.ToUpper(emote), strings.ToUpper(userID), delta).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Error updating %s %s stats for %s", emote, column, userID)
+	}
+
+	return count, nil
+}
+
+func (s *sqlStore) PruneRecorded(before time.Time) (int, error) {
+	res, err := s.db.Exec(s.query(`DELETE FROM emote_records WHERE recorded_at < ?`), before.Unix())
+	if err != nil {
+		return 0, errors.Wrap(err, "Error pruning recorded emotes")
+	}
+
+	pruned, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "Error pruning recorded emotes")
+	}
+
+	return int(pruned), nil
+}
+
+func (s *sqlStore) getSetting(scope string, id string, name string) (string, error) {
+	value := ""
+
+	err := s.db.QueryRow(s.query(`SELECT value FROM settings WHERE scope = ? AND id = ? AND name = ?`),
+		scope, strings.ToUpper(id), name).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "Error getting %s setting %s for %s", strings.ToLower(scope), name, id)
+	}
+
+	return value, nil
+}
+
+func (s *sqlStore) setSetting(scope string, id string, name string, value string) error {
+	var err error
+
+	if value == "" {
+		_, err = s.db.Exec(s.query(`DELETE FROM settings WHERE scope = ? AND id = ? AND name = ?`), scope, strings.ToUpper(id), name)
+	} else {
+		_, err = s.db.Exec(s.query(`INSERT INTO settings (scope, id, name, value) VALUES (?, ?, ?, ?)
+			ON CONFLICT (scope, id, name) DO UPDATE SET value = excluded.value`), scope, strings.ToUpper(id), name, value)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "Error setting %s setting %s for %s", strings.ToLower(scope), name, id)
+	}
+
+	return nil
+}
+
+func (s *sqlStore) GetGuildLocale(guildID string) (string, error) {
+	return getGuildLocale(s, guildID)
+}
+
+func (s *sqlStore) SetGuildLocale(guildID string, locale string) error {
+	return setGuildLocale(s, guildID, locale)
+}
+
+func (s *sqlStore) GetUserLocale(userID string) (string, error) {
+	return getUserLocale(s, userID)
+}
+
+func (s *sqlStore) SetUserLocale(userID string, locale string) error {
+	return setUserLocale(s, userID, locale)
+}
+
+func (s *sqlStore) GetGuildTheme(guildID string) (GuildTheme, error) {
+	return getGuildTheme(s, guildID)
+}
+
+func (s *sqlStore) SetGuildTheme(guildID string, theme GuildTheme) error {
+	return setGuildTheme(s, guildID, theme)
+}
+
+func (s *sqlStore) GetUserOptOut(userID string) (bool, error) {
+	return getUserOptOut(s, userID)
+}
+
+func (s *sqlStore) SetUserOptOut(userID string, optOut bool) error {
+	return setUserOptOut(s, userID, optOut)
+}
+
+// Export reads every stat and setting
+func (s *sqlStore) Export() (*Export, error) {
+	e := &Export{
+		Version:  exportVersion,
+		Stats:    []StatRecord{},
+		Settings: []SettingRecord{},
+	}
+
+	rows, err := s.db.Query(`SELECT emote, user_id, sent, received FROM emote_stats ORDER BY emote, user_id`)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error exporting stats")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r StatRecord
+		if err := rows.Scan(&r.Emote, &r.UserID, &r.Sent, &r.Received); err != nil {
+			return nil, errors.Wrap(err, "Error exporting stats")
+		}
+
+		e.Stats = append(e.Stats, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "Error exporting stats")
+	}
+
+	rows, err = s.db.Query(`SELECT scope, id, name, value FROM settings ORDER BY scope, id, name`)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error exporting settings")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r SettingRecord
+		if err := rows.Scan(&r.Scope, &r.ID, &r.Name, &r.Value); err != nil {
+			return nil, errors.Wrap(err, "Error exporting settings")
+		}
+
+		e.Settings = append(e.Settings, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "Error exporting settings")
+	}
+
+	return e, nil
+}
+
+// Import writes every stat and setting in an export in a single transaction.
+// Existing values are overwritten, and when replace is set everything else is removed first.
+func (s *sqlStore) Import(e *Export, replace bool) error {
+	if err := checkExport(e); err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "Error importing database")
+	}
+	defer tx.Rollback()
+
+	if replace {
+		for _, table := range []string{"emote_stats", "settings"} {
+			if _, err := tx.Exec(`DELETE FROM ` + table); err != nil {
+				return errors.Wrapf(err, "Error clearing %s", table)
+			}
+		}
+	}
+
+	for _, r := range e.Stats {
+		_, err := tx.Exec(s.query(`INSERT INTO emote_stats (emote, user_id, sent, received) VALUES (?, ?, ?, ?)
+			ON CONFLICT (emote, user_id) DO UPDATE SET sent = excluded.sent, received = excluded.received`),
+			strings.ToUpper(r.Emote), strings.ToUpper(r.UserID), r.Sent, r.Received)
+		if err != nil {
+			return errors.Wrapf(err, "Error importing %s stats for %s", r.Emote, r.UserID)
+		}
+	}
+
+	for _, r := range e.Settings {
+		_, err := tx.Exec(s.query(`INSERT INTO settings (scope, id, name, value) VALUES (?, ?, ?, ?)
+			ON CONFLICT (scope, id, name) DO UPDATE SET value = excluded.value`),
+			r.Scope, strings.ToUpper(r.ID), r.Name, r.Value)
+		if err != nil {
+			return errors.Wrapf(err, "Error importing setting %s for %s", r.Name, r.ID)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "Error importing database")
+	}
+
+	return nil
+}
+
+func (s *sqlStore) Close() error {
+	return s.db.Close()
+}
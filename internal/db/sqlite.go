@@ -0,0 +1,10 @@
+package db
+
+var (
+	sqliteDialect sqlDialect = sqlDialect{
+		driver: "sqlite3",
+		placeholder: func(n int) string {
+			return "?"
+		},
+	}
+)
@@ -0,0 +1,12 @@
+//go:build cgo
+// +build cgo
+
+package db
+
+import (
+	// Registers the sqlite3 driver, which needs cgo
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// sqliteSupported is set when the bot was built with the cgo sqlite driver
+const sqliteSupported = true
@@ -0,0 +1,8 @@
+//go:build !cgo
+// +build !cgo
+
+package db
+
+// sqliteSupported is set when the bot was built with the cgo sqlite driver.
+// Without cgo the driver only fails once it is used, so the sqlite store refuses to open instead.
+const sqliteSupported = false
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Store drivers
+const (
+	StoreBolt     = "bolt"
+	StoreSQLite   = "sqlite"
+	StorePostgres = "postgres"
+)
+
+// EmoteCounts holds how many times a user has sent and received an emote
+type EmoteCounts struct {
+	Sent     int
+	Received int
+}
+
+// StatsStore stores how many times users have sent and received each emote
+type StatsStore interface {
+	// GetEmoteCountsForUser returns how many times a user has sent and received an emote
+	GetEmoteCountsForUser(emote string, userID string) (sent int, received int, err error)
+	// GetUserEmoteCounts returns a user's counts for every emote they have sent or received, keyed by lowercase verb
+	GetUserEmoteCounts(userID string) (map[string]EmoteCounts, error)
+	// AddEmoteSentUsage adds to how many times a user has sent an emote, returning the new count
+	AddEmoteSentUsage(emote string, userID string, delta int) (int, error)
+	// AddEmoteReceivedUsage adds to how many times a user has received an emote, returning the new count
+	AddEmoteReceivedUsage(emote string, userID string, delta int) (int, error)
+	// RecordEmote counts an emote sent by a user and, unless receiverID is empty, received by another in one transaction.
+	// A non-empty key is remembered, returning false without counting again if it has already been recorded.
+	RecordEmote(key string, emote string, senderID string, receiverID string) (bool, error)
+	// PruneRecorded forgets keys recorded before a time, returning how many were removed
+	PruneRecorded(before time.Time) (int, error)
+	Close() error
+}
+
+// Store stores stats and settings
+type Store interface {
+	StatsStore
+	SettingsStore
+	// Export reads every stat and setting
+	Export() (*Export, error)
+	// Import writes every stat and setting in an export in a single transaction.
+	// Existing values are overwritten, and when replace is set everything else is removed first.
+	Import(e *Export, replace bool) error
+}
+
+// OpenStore opens the store for a driver.
+// The bolt driver opens databaseFile, which only one bot can have open at a time.
+// The sql drivers connect using dsn and can be shared by several bots.
+func OpenStore(driver string, dsn string, databaseFile string) (Store, error) {
+	switch driver {
+	case StoreBolt, "":
+		return OpenOrConfigureDatabase(databaseFile)
+	case StoreSQLite:
+		if !sqliteSupported {
+			return nil, errors.New("The sqlite store needs the bot built with cgo, build it with CGO_ENABLED=1 and a C compiler or use the bolt or postgres store")
+		}
+
+		return openSQLStore(sqliteDialect, dsn)
+	case StorePostgres:
+		return openSQLStore(postgresDialect, dsn)
+	default:
+		return nil, errors.Errorf("Unknown store driver %q, expected %s, %s or %s", driver, StoreBolt, StoreSQLite, StorePostgres)
+	}
+}
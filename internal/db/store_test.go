@@ -0,0 +1,352 @@
+package db
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// postgresDSNEnv names the environment variable holding a postgres database to test against, which is emptied first
+const postgresDSNEnv = "SOPHIE_TEST_POSTGRES_DSN"
+
+// testStores opens an empty store for every driver in dir, skipping sqlite without cgo and postgres unless a database is given
+func testStores(t *testing.T, dir string) map[string]Store {
+	stores := map[string]Store{}
+
+	database, err := OpenStore(StoreBolt, "", filepath.Join(dir, "data.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stores[StoreBolt] = database
+
+	if sqliteSupported {
+		sqlite, err := OpenStore(StoreSQLite, filepath.Join(dir, "sophie.sqlite"), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		stores[StoreSQLite] = sqlite
+	} else {
+		t.Log("Skipping sqlite, it needs cgo")
+	}
+
+	if dsn := os.Getenv(postgresDSNEnv); dsn != "" {
+		postgres, err := OpenStore(StorePostgres, dsn, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, table := range []string{"emote_stats", "emote_records", "settings"} {
+			if _, err := postgres.(*sqlStore).db.Exec("DELETE FROM " + table); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		stores[StorePostgres] = postgres
+	} else {
+		t.Logf("Skipping postgres, set %s to test it", postgresDSNEnv)
+	}
+
+	return stores
+}
+
+// forEachStore runs a test against an empty store for every driver
+func forEachStore(t *testing.T, test func(t *testing.T, store Store)) {
+	dir, err := ioutil.TempDir("", "sophie-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for driver, store := range testStores(t, dir) {
+		store := store
+		t.Run(driver, func(t *testing.T) {
+			defer store.Close()
+			test(t, store)
+		})
+	}
+}
+
+func TestStoreEmoteCounts(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		sent, received, err := store.GetEmoteCountsForUser("hug", "1")
+		if err != nil || sent != 0 || received != 0 {
+			t.Fatalf("GetEmoteCountsForUser on an empty store = %d, %d, %v, want 0, 0, nil", sent, received, err)
+		}
+
+		if n, err := store.AddEmoteSentUsage("hug", "1", 2); err != nil || n != 2 {
+			t.Fatalf("AddEmoteSentUsage = %d, %v, want 2, nil", n, err)
+		}
+
+		if n, err := store.AddEmoteSentUsage("HUG", "1", 1); err != nil || n != 3 {
+			t.Fatalf("AddEmoteSentUsage with another case = %d, %v, want 3, nil", n, err)
+		}
+
+		if n, err := store.AddEmoteReceivedUsage("hug", "1", 1); err != nil || n != 1 {
+			t.Fatalf("AddEmoteReceivedUsage = %d, %v, want 1, nil", n, err)
+		}
+
+		if _, err := store.AddEmoteReceivedUsage("pat", "1", 4); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := store.AddEmoteSentUsage("hug", "2", 5); err != nil {
+			t.Fatal(err)
+		}
+
+		sent, received, err = store.GetEmoteCountsForUser("hug", "1")
+		if err != nil || sent != 3 || received != 1 {
+			t.Fatalf("GetEmoteCountsForUser = %d, %d, %v, want 3, 1, nil", sent, received, err)
+		}
+
+		counts, err := store.GetUserEmoteCounts("1")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := map[string]EmoteCounts{"hug": {Sent: 3, Received: 1}, "pat": {Received: 4}}
+		if len(counts) != len(want) {
+			t.Fatalf("GetUserEmoteCounts = %v, want %v", counts, want)
+		}
+		for verb, c := range want {
+			if counts[verb] != c {
+				t.Fatalf("GetUserEmoteCounts = %v, want %v", counts, want)
+			}
+		}
+	})
+}
+
+func TestStoreRecordEmote(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		tests := []struct {
+			key      string
+			receiver string
+			recorded bool
+		}{
+			{key: "100", receiver: "2", recorded: true},
+			{key: "100", receiver: "2", recorded: false},
+			{key: "101", receiver: "", recorded: true},
+			{key: "", receiver: "2", recorded: true},
+			{key: "", receiver: "2", recorded: true},
+		}
+
+		for _, test := range tests {
+			recorded, err := store.RecordEmote(test.key, "hug", "1", test.receiver)
+			if err != nil || recorded != test.recorded {
+				t.Fatalf("RecordEmote(%q) = %t, %v, want %t, nil", test.key, recorded, err, test.recorded)
+			}
+		}
+
+		sent, received, err := store.GetEmoteCountsForUser("hug", "1")
+		if err != nil || sent != 4 || received != 0 {
+			t.Fatalf("sender counts = %d, %d, %v, want 4, 0, nil", sent, received, err)
+		}
+
+		sent, received, err = store.GetEmoteCountsForUser("hug", "2")
+		if err != nil || sent != 0 || received != 3 {
+			t.Fatalf("receiver counts = %d, %d, %v, want 0, 3, nil", sent, received, err)
+		}
+	})
+}
+
+func TestStoreSettings(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		if locale, err := store.GetGuildLocale("10"); err != nil || locale != "" {
+			t.Fatalf("GetGuildLocale on an empty store = %q, %v, want empty", locale, err)
+		}
+
+		if err := store.SetGuildLocale("10", "de"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := store.SetUserLocale("10", "fr"); err != nil {
+			t.Fatal(err)
+		}
+
+		if locale, err := store.GetGuildLocale("10"); err != nil || locale != "de" {
+			t.Fatalf("GetGuildLocale = %q, %v, want de", locale, err)
+		}
+
+		if locale, err := store.GetUserLocale("10"); err != nil || locale != "fr" {
+			t.Fatalf("GetUserLocale = %q, %v, want fr, guild and user settings must be kept apart", locale, err)
+		}
+
+		if err := store.SetGuildLocale("10", "es"); err != nil {
+			t.Fatal(err)
+		}
+
+		if locale, err := store.GetGuildLocale("10"); err != nil || locale != "es" {
+			t.Fatalf("GetGuildLocale after changing it = %q, %v, want es", locale, err)
+		}
+
+		if err := store.SetGuildLocale("10", ""); err != nil {
+			t.Fatal(err)
+		}
+
+		if locale, err := store.GetGuildLocale("10"); err != nil || locale != "" {
+			t.Fatalf("GetGuildLocale after clearing it = %q, %v, want empty", locale, err)
+		}
+
+		black, on := 0, true
+		if err := store.SetGuildTheme("10", GuildTheme{Color: &black, ShowAuthor: &on}); err != nil {
+			t.Fatal(err)
+		}
+
+		theme, err := store.GetGuildTheme("10")
+		if err != nil || theme.Color == nil || *theme.Color != 0 || theme.ShowAuthor == nil || !*theme.ShowAuthor || theme.ShowReceiverAvatar != nil {
+			t.Fatalf("GetGuildTheme = %+v, %v, want black with the author shown", theme, err)
+		}
+
+		if err := store.SetGuildTheme("10", GuildTheme{}); err != nil {
+			t.Fatal(err)
+		}
+
+		if theme, err := store.GetGuildTheme("10"); err != nil || theme != (GuildTheme{}) {
+			t.Fatalf("GetGuildTheme after resetting it = %+v, %v, want no overrides", theme, err)
+		}
+
+		for _, optOut := range []bool{true, false} {
+			if err := store.SetUserOptOut("10", optOut); err != nil {
+				t.Fatal(err)
+			}
+
+			if got, err := store.GetUserOptOut("10"); err != nil || got != optOut {
+				t.Fatalf("GetUserOptOut = %t, %v, want %t", got, err, optOut)
+			}
+		}
+	})
+}
+
+// sortExport orders an export's records, which stores may read in different orders
+func sortExport(e *Export) {
+	sort.Slice(e.Stats, func(i, j int) bool {
+		a, b := e.Stats[i], e.Stats[j]
+		return a.Emote+"|"+a.UserID < b.Emote+"|"+b.UserID
+	})
+
+	sort.Slice(e.Settings, func(i, j int) bool {
+		a, b := e.Settings[i], e.Settings[j]
+		return a.Scope+"|"+a.ID+"|"+a.Name < b.Scope+"|"+b.ID+"|"+b.Name
+	})
+}
+
+func TestStoreExportImport(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		if _, err := store.RecordEmote("100", "hug", "1", "2"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := store.SetGuildLocale("10", "de"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := store.SetUserOptOut("11", true); err != nil {
+			t.Fatal(err)
+		}
+
+		e, err := store.Export()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sortExport(e)
+
+		want := &Export{
+			Version: exportVersion,
+			Stats: []StatRecord{
+				{Emote: "HUG", UserID: "1", Sent: 1},
+				{Emote: "HUG", UserID: "2", Received: 1},
+			},
+			Settings: []SettingRecord{
+				{Scope: ScopeGuild, ID: "10", Name: "Locale", Value: "de"},
+				{Scope: ScopeUser, ID: "11", Name: "OptOut", Value: "true"},
+			},
+		}
+		if !reflect.DeepEqual(e, want) {
+			t.Fatalf("Export = %+v, want %+v", e, want)
+		}
+
+		bad := &Export{Version: exportVersion, Stats: []StatRecord{{Emote: "hug", UserID: "1", Sent: 9}}, Settings: []SettingRecord{{Scope: "CHANNEL", ID: "1", Name: "Locale", Value: "de"}}}
+		if err := store.Import(bad, true); err == nil {
+			t.Fatal("Import accepted a setting with an unknown scope")
+		}
+
+		update := &Export{
+			Version:  exportVersion,
+			Stats:    []StatRecord{{Emote: "hug", UserID: "1", Sent: 5}},
+			Settings: []SettingRecord{{Scope: ScopeUser, ID: "12", Name: "Locale", Value: "fr"}},
+		}
+
+		if err := store.Import(update, false); err != nil {
+			t.Fatal(err)
+		}
+
+		if sent, _, err := store.GetEmoteCountsForUser("hug", "1"); err != nil || sent != 5 {
+			t.Fatalf("sent after importing = %d, %v, want 5", sent, err)
+		}
+
+		if _, received, err := store.GetEmoteCountsForUser("hug", "2"); err != nil || received != 1 {
+			t.Fatalf("received after importing = %d, %v, want it kept at 1", received, err)
+		}
+
+		if locale, err := store.GetUserLocale("12"); err != nil || locale != "fr" {
+			t.Fatalf("GetUserLocale after importing = %q, %v, want fr", locale, err)
+		}
+
+		if locale, err := store.GetGuildLocale("10"); err != nil || locale != "de" {
+			t.Fatalf("GetGuildLocale after importing = %q, %v, want it kept as de", locale, err)
+		}
+
+		if err := store.Import(update, true); err != nil {
+			t.Fatal(err)
+		}
+
+		e, err = store.Export()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sortExport(e)
+
+		want = &Export{
+			Version:  exportVersion,
+			Stats:    []StatRecord{{Emote: "HUG", UserID: "1", Sent: 5}},
+			Settings: []SettingRecord{{Scope: ScopeUser, ID: "12", Name: "Locale", Value: "fr"}},
+		}
+		if !reflect.DeepEqual(e, want) {
+			t.Fatalf("Export after replacing = %+v, want %+v", e, want)
+		}
+	})
+}
+
+func TestStorePruneRecorded(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		for _, key := range []string{"100", "101"} {
+			if _, err := store.RecordEmote(key, "hug", "1", ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if pruned, err := store.PruneRecorded(time.Now().Add(-time.Hour)); err != nil || pruned != 0 {
+			t.Fatalf("PruneRecorded before the keys were recorded = %d, %v, want 0", pruned, err)
+		}
+
+		if recorded, err := store.RecordEmote("100", "hug", "1", ""); err != nil || recorded {
+			t.Fatalf("RecordEmote with a kept key = %t, %v, want false", recorded, err)
+		}
+
+		if pruned, err := store.PruneRecorded(time.Now().Add(time.Minute)); err != nil || pruned != 2 {
+			t.Fatalf("PruneRecorded after the keys were recorded = %d, %v, want 2", pruned, err)
+		}
+
+		// Pruning only forgets keys, the counts stay
+		if sent, _, err := store.GetEmoteCountsForUser("hug", "1"); err != nil || sent != 2 {
+			t.Fatalf("sent after pruning = %d, %v, want 2", sent, err)
+		}
+
+		if recorded, err := store.RecordEmote("100", "hug", "1", ""); err != nil || !recorded {
+			t.Fatalf("RecordEmote with a pruned key = %t, %v, want true", recorded, err)
+		}
+	})
+}
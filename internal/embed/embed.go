@@ -15,6 +15,7 @@ const (
 	EmbedLimitFieldName   = 256
 	EmbedLimitField       = 25
 	EmbedLimitFooter      = 2048
+	EmbedLimitAuthorName  = 256
 	EmbedLimit            = 4000
 )
 
@@ -25,32 +26,21 @@ func NewEmbed() *Embed {
 
 //SetTitle ...
 func (e *Embed) SetTitle(name string) *Embed {
-	e.Title = name
+	e.Title = truncate(name, EmbedLimitTitle)
 	return e
 }
 
 //SetDescription [desc]
 func (e *Embed) SetDescription(description string) *Embed {
-	if len(description) > 2048 {
-		description = description[:2048]
-	}
-	e.Description = description
+	e.Description = truncate(description, EmbedLimitDescription)
 	return e
 }
 
 //AddField [name] [value]
 func (e *Embed) AddField(name, value string) *Embed {
-	if len(value) > 1024 {
-		value = value[:1024]
-	}
-
-	if len(name) > 1024 {
-		name = name[:1024]
-	}
-
 	e.Fields = append(e.Fields, &discordgo.MessageEmbedField{
-		Name:  name,
-		Value: value,
+		Name:  truncate(name, EmbedLimitFieldName),
+		Value: truncate(value, EmbedLimitFieldValue),
 	})
 
 	return e
@@ -78,7 +68,7 @@ func (e *Embed) SetFooter(args ...string) *Embed {
 
 	e.Footer = &discordgo.MessageEmbedFooter{
 		IconURL:      iconURL,
-		Text:         text,
+		Text:         truncate(text, EmbedLimitFooter),
 		ProxyIconURL: proxyURL,
 	}
 
@@ -153,7 +143,7 @@ func (e *Embed) SetAuthor(args ...string) *Embed {
 	}
 
 	e.Author = &discordgo.MessageEmbedAuthor{
-		Name:         name,
+		Name:         truncate(name, EmbedLimitAuthorName),
 		IconURL:      iconURL,
 		URL:          URL,
 		ProxyIconURL: proxyURL,
@@ -182,55 +172,95 @@ func (e *Embed) InlineAllFields() *Embed {
 	return e
 }
 
-// Truncate truncates any embed value over the character limit.
+// Truncate truncates any embed value over the character limit, then trims the embed to fit the total limit.
 func (e *Embed) Truncate() *Embed {
 	e.TruncateDescription()
 	e.TruncateFields()
 	e.TruncateFooter()
 	e.TruncateTitle()
+	e.TruncateAuthor()
+	e.TruncateTotal()
 	return e
 }
 
 // TruncateFields truncates fields that are too long
 func (e *Embed) TruncateFields() *Embed {
-	if len(e.Fields) > 25 {
+	if len(e.Fields) > EmbedLimitField {
 		e.Fields = e.Fields[:EmbedLimitField]
 	}
 
 	for _, v := range e.Fields {
-
-		if len(v.Name) > EmbedLimitFieldName {
-			v.Name = v.Name[:EmbedLimitFieldName]
-		}
-
-		if len(v.Value) > EmbedLimitFieldValue {
-			v.Value = v.Value[:EmbedLimitFieldValue]
-		}
-
+		v.Name = truncate(v.Name, EmbedLimitFieldName)
+		v.Value = truncate(v.Value, EmbedLimitFieldValue)
 	}
 	return e
 }
 
 // TruncateDescription ...
 func (e *Embed) TruncateDescription() *Embed {
-	if len(e.Description) > EmbedLimitDescription {
-		e.Description = e.Description[:EmbedLimitDescription]
-	}
+	e.Description = truncate(e.Description, EmbedLimitDescription)
 	return e
 }
 
 // TruncateTitle ...
 func (e *Embed) TruncateTitle() *Embed {
-	if len(e.Title) > EmbedLimitTitle {
-		e.Title = e.Title[:EmbedLimitTitle]
-	}
+	e.Title = truncate(e.Title, EmbedLimitTitle)
 	return e
 }
 
 // TruncateFooter ...
 func (e *Embed) TruncateFooter() *Embed {
-	if e.Footer != nil && len(e.Footer.Text) > EmbedLimitFooter {
-		e.Footer.Text = e.Footer.Text[:EmbedLimitFooter]
+	if e.Footer != nil {
+		e.Footer.Text = truncate(e.Footer.Text, EmbedLimitFooter)
+	}
+	return e
+}
+
+// TruncateAuthor ...
+func (e *Embed) TruncateAuthor() *Embed {
+	if e.Author != nil {
+		e.Author.Name = truncate(e.Author.Name, EmbedLimitAuthorName)
+	}
+	return e
+}
+
+// TruncateTotal drops fields from the end, then shortens the description, footer and title until the embed fits the total limit
+func (e *Embed) TruncateTotal() *Embed {
+	excess := e.Length() - EmbedLimit
+
+	for excess > 0 && len(e.Fields) > 0 {
+		e.Fields = e.Fields[:len(e.Fields)-1]
+		excess = e.Length() - EmbedLimit
 	}
+
+	if excess > 0 {
+		keep := runeCount(e.Description) - excess
+		if keep < 0 {
+			keep = 0
+		}
+
+		e.Description = truncate(e.Description, keep)
+		excess = e.Length() - EmbedLimit
+	}
+
+	if excess > 0 && e.Footer != nil {
+		keep := runeCount(e.Footer.Text) - excess
+		if keep < 0 {
+			keep = 0
+		}
+
+		e.Footer.Text = truncate(e.Footer.Text, keep)
+		excess = e.Length() - EmbedLimit
+	}
+
+	if excess > 0 {
+		keep := runeCount(e.Title) - excess
+		if keep < 0 {
+			keep = 0
+		}
+
+		e.Title = truncate(e.Title, keep)
+	}
+
 	return e
 }
@@ -1,29 +1,40 @@
 package embed
 
 import (
-	"context"
-	"errors"
-	"fmt"
-
-	"github.com/SonarBeserk/sophie-go/internal/db"
 	"github.com/SonarBeserk/sophie-go/internal/emote"
 	"github.com/bwmarrin/discordgo"
 )
 
-var (
-	databaseCtx ContextKey = "db"
+// Target describes who an emote is aimed at
+type Target int
+
+// Emote targets
+const (
+	TargetNone Target = iota
+	TargetUser
+	TargetSelf
+	TargetBot
 )
 
-// ContextKey is used to store a value in context
-type ContextKey string
+// DefaultColor is used for emotes without a color
+const DefaultColor = 0x00ff00
 
-// CreateEmoteEmbed creates an embed
-func CreateEmoteEmbed(ctx context.Context, em emote.Emote, sender *discordgo.Member, receiver *discordgo.Member, image string, message string) (*discordgo.MessageEmbed, error) {
-	db, ok := ctx.Value(databaseCtx).(db.Database)
-	if !ok {
-		return nil, errors.New("Failed to get database from context")
-	}
+// Style controls how an emote embed looks
+type Style struct {
+	// Color is nil to use DefaultColor, so black can still be picked
+	Color              *int
+	ShowAuthor         bool
+	ShowReceiverAvatar bool
+}
 
+// EmoteCounts are the counts shown in an emote's footer, the receiver's when there is one or else the sender's
+type EmoteCounts struct {
+	Sent     int
+	Received int
+}
+
+// RenderEmoteEmbed creates an embed for an emote, it has no side effects so stats must be recorded separately
+func RenderEmoteEmbed(em emote.Emote, sender *discordgo.Member, receiver *discordgo.Member, target Target, locale string, style Style, image string, message string, counts EmoteCounts) (*discordgo.MessageEmbed, error) {
 	senderName := sender.User.Username
 
 	if sender.Nick != "" {
@@ -44,64 +55,96 @@ func CreateEmoteEmbed(ctx context.Context, em emote.Emote, sender *discordgo.Mem
 	}
 
 	description := ""
-	stats := ""
+	footer := ""
+
+	// Targeting yourself is shown like an emote without a receiver
+	if target == TargetSelf {
+		receiver = nil
+	}
+
+	var err error
 
 	if sender != nil && receiver == nil {
-		sentCount, receivedCount, err := db.GetEmoteCountsForUser(em.Verb, sender.User.ID)
-		if err != nil {
-			return nil, err
+		field := emote.FieldSenderMessage
+		if target == TargetSelf && em.SelfMessage != "" {
+			field = emote.FieldSelfMessage
 		}
 
-		sentCount = sentCount + 1
-
-		err = db.SetEmoteSentUsage(em.Verb, sender.User.ID, sentCount)
+		description, err = em.Render(locale, field, emote.MessageData{
+			Sender:  senderName,
+			Message: message,
+		})
 		if err != nil {
 			return nil, err
 		}
 
-		description = fmt.Sprintf(em.SenderMessage, senderName, message)
-		stats = fmt.Sprintf(em.SenderDescription, senderName, sentCount, receivedCount)
-	}
-
-	if sender != nil && receiver != nil {
-		senderSentCount, err := db.GetEmoteSentUsage(em.Verb, sender.User.ID)
+		footer, err = em.Render(locale, emote.FieldSenderDescription, emote.MessageData{
+			Sender:        senderName,
+			SentCount:     counts.Sent,
+			ReceivedCount: counts.Received,
+		})
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		senderSentCount = senderSentCount + 1
-
-		err = db.SetEmoteSentUsage(em.Verb, sender.User.ID, senderSentCount)
-		if err != nil {
-			return nil, err
+	if sender != nil && receiver != nil {
+		field := emote.FieldReceiverMessage
+		if target == TargetBot && em.BotMessage != "" {
+			field = emote.FieldBotMessage
 		}
 
-		sentCount, err := db.GetEmoteSentUsage(em.Verb, receiver.User.ID)
+		description, err = em.Render(locale, field, emote.MessageData{
+			Sender:   senderName,
+			Receiver: receiverName,
+			Message:  message,
+		})
 		if err != nil {
 			return nil, err
 		}
 
-		receivedCount, err := db.GetEmoteReceivedUsage(em.Verb, receiver.User.ID)
+		footer, err = em.Render(locale, emote.FieldReceiverDescription, emote.MessageData{
+			Receiver:      receiverName,
+			SentCount:     counts.Sent,
+			ReceivedCount: counts.Received,
+		})
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		receivedCount = receivedCount + 1
+	title, err := em.Render(locale, emote.FieldTitle, emote.MessageData{
+		Sender:   senderName,
+		Receiver: receiverName,
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		err = db.SetEmoteReceivedUsage(em.Verb, receiver.User.ID, receivedCount)
-		if err != nil {
-			return nil, err
-		}
+	color := DefaultColor
+	if style.Color != nil {
+		color = *style.Color
+	}
 
-		description = fmt.Sprintf(em.ReceiverMessage, senderName, receiverName, message)
-		stats = fmt.Sprintf(em.ReceiverDescription, receiverName, sentCount, receivedCount)
+	// Discord shows a color of 0 as no color at all, so black is sent as the closest color it will show
+	if color == 0 {
+		color = 0x000001
 	}
 
 	embed := NewEmbed().
+		SetTitle(title).
 		SetDescription(description).
 		SetImage(image).
-		SetFooter(stats).
-		SetColor(0x00ff00).MessageEmbed
+		SetFooter(footer).
+		SetColor(color)
+
+	if style.ShowAuthor {
+		embed.SetAuthor(senderName, sender.User.AvatarURL(""))
+	}
+
+	if style.ShowReceiverAvatar && receiver != nil {
+		embed.SetThumbnail(receiver.User.AvatarURL(""))
+	}
 
-	return embed, nil
+	return embed.Truncate().MessageEmbed, nil
 }
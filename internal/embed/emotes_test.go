@@ -0,0 +1,149 @@
+package embed
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/SonarBeserk/sophie-go/internal/emote"
+	"github.com/bwmarrin/discordgo"
+)
+
+var update = flag.Bool("update", false, "Rewrite golden files with the current output")
+
+// testEmote is rendered by every golden test
+var testEmote emote.Emote = emote.Emote{
+	Verb:                "hug",
+	SenderMessage:       "**{{.Sender}}** wants a hug {{.Message}}",
+	SenderDescription:   `{{.Sender}} has hugged {{plural .SentCount "person" "people"}} and been hugged by {{plural .ReceivedCount "person" "people"}}`,
+	ReceiverMessage:     "**{{.Sender}}** hugs **{{.Receiver}}** {{.Message}}",
+	ReceiverDescription: `{{.Receiver}} has hugged {{plural .SentCount "person" "people"}} and been hugged by {{plural .ReceivedCount "person" "people"}}`,
+	SelfMessage:         "**{{.Sender}}** hugs themselves {{.Message}}",
+	BotMessage:          "**{{.Sender}}** hugs the bot, how sweet {{.Message}}",
+	Translations: map[string]emote.Translation{
+		"de": {
+			SenderMessage:   "**{{.Sender}}** möchte eine Umarmung {{.Message}}",
+			ReceiverMessage: "**{{.Sender}}** umarmt **{{.Receiver}}** {{.Message}}",
+		},
+	},
+}
+
+var (
+	testSender   *discordgo.Member = &discordgo.Member{User: &discordgo.User{ID: "1", Username: "alice", Avatar: "a1"}, Nick: "Ali"}
+	testReceiver *discordgo.Member = &discordgo.Member{User: &discordgo.User{ID: "2", Username: "bob", Avatar: "b2"}}
+)
+
+// color returns a style color, which is a pointer so black can be told apart from no color
+func color(c int) *int {
+	return &c
+}
+
+func TestRenderEmoteEmbed(t *testing.T) {
+	tests := []struct {
+		name     string
+		emote    emote.Emote
+		receiver *discordgo.Member
+		target   Target
+		locale   string
+		style    Style
+		message  string
+		counts   EmoteCounts
+	}{
+		{name: "sender", target: TargetNone, locale: "en", counts: EmoteCounts{Sent: 1}},
+		{name: "receiver", receiver: testReceiver, target: TargetUser, locale: "en", message: "have a great day", counts: EmoteCounts{Sent: 2, Received: 5}},
+		{name: "self", receiver: testSender, target: TargetSelf, locale: "en", counts: EmoteCounts{Sent: 3, Received: 1}},
+		{name: "bot", receiver: testReceiver, target: TargetBot, locale: "en", counts: EmoteCounts{Received: 1}},
+		{name: "translated", receiver: testReceiver, target: TargetUser, locale: "de", counts: EmoteCounts{Received: 1}},
+		{name: "styled", receiver: testReceiver, target: TargetUser, locale: "en", style: Style{Color: color(0xff88cc), ShowAuthor: true, ShowReceiverAvatar: true}, counts: EmoteCounts{Received: 1}},
+		{name: "black", receiver: testReceiver, target: TargetUser, locale: "en", style: Style{Color: color(0x000000)}, counts: EmoteCounts{Received: 1}},
+		{
+			name: "title",
+			emote: emote.Emote{
+				Verb:                "wave",
+				Title:               "{{.Sender}} waves",
+				SenderMessage:       "**{{.Sender}}** waves {{.Message}}",
+				SenderDescription:   "{{.SentCount}} {{.ReceivedCount}}",
+				ReceiverMessage:     "**{{.Sender}}** waves at **{{.Receiver}}** {{.Message}}",
+				ReceiverDescription: "{{.SentCount}} {{.ReceivedCount}}",
+				Color:               "#3366ff",
+			},
+			target: TargetNone,
+			locale: "en",
+			counts: EmoteCounts{Sent: 7, Received: 8},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			em := test.emote
+			if em.Verb == "" {
+				em = testEmote
+			}
+
+			if err := em.Compile(); err != nil {
+				t.Fatal(err)
+			}
+
+			style := test.style
+			if style.Color == nil {
+				style.Color = em.EmbedColor()
+			}
+
+			got, err := RenderEmoteEmbed(em, testSender, test.receiver, test.target, test.locale, style, "https://example.com/"+em.Verb+".gif", test.message, test.counts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := json.MarshalIndent(got, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			data = append(data, '\n')
+
+			golden := filepath.Join("testdata", "emote_"+test.name+".golden.json")
+			if *update {
+				if err := ioutil.WriteFile(golden, data, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v, run go test with -update to create it", err)
+			}
+
+			if string(data) != string(want) {
+				t.Errorf("embed differs from %s, run go test with -update if the change is intended\ngot:\n%s\nwant:\n%s", golden, data, want)
+			}
+		})
+	}
+}
+
+// RenderEmoteEmbed must not change anything, so rendering twice gives the same embed
+func TestRenderEmoteEmbedIsPure(t *testing.T) {
+	em := testEmote
+	if err := em.Compile(); err != nil {
+		t.Fatal(err)
+	}
+
+	render := func() string {
+		e, err := RenderEmoteEmbed(em, testSender, testReceiver, TargetUser, "en", Style{}, "https://example.com/hug.gif", "", EmoteCounts{Sent: 1, Received: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return string(data)
+	}
+
+	if first, second := render(), render(); first != second {
+		t.Fatalf("rendering twice gave different embeds:\n%s\n%s", first, second)
+	}
+}
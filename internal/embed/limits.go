@@ -0,0 +1,101 @@
+package embed
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ellipsis is appended to truncated text
+const ellipsis = "…"
+
+// ValidationError lists every way an embed breaks Discord's limits
+type ValidationError struct {
+	Violations []string
+}
+
+func (e *ValidationError) Error() string {
+	return "embed exceeds limits: " + strings.Join(e.Violations, "; ")
+}
+
+// Length returns the number of characters in the embed that count towards the total limit
+func (e *Embed) Length() int {
+	length := runeCount(e.Title) + runeCount(e.Description)
+
+	for _, f := range e.Fields {
+		length += runeCount(f.Name) + runeCount(f.Value)
+	}
+
+	if e.Footer != nil {
+		length += runeCount(e.Footer.Text)
+	}
+
+	if e.Author != nil {
+		length += runeCount(e.Author.Name)
+	}
+
+	return length
+}
+
+// Validate checks the embed against Discord's limits without changing it
+func (e *Embed) Validate() error {
+	violations := []string{}
+
+	check := func(name string, value string, limit int) {
+		if n := runeCount(value); n > limit {
+			violations = append(violations, fmt.Sprintf("%s has %d characters, the limit is %d", name, n, limit))
+		}
+	}
+
+	if !utf8.ValidString(e.Title) || !utf8.ValidString(e.Description) {
+		violations = append(violations, "title or description is not valid UTF-8")
+	}
+
+	check("title", e.Title, EmbedLimitTitle)
+	check("description", e.Description, EmbedLimitDescription)
+
+	if len(e.Fields) > EmbedLimitField {
+		violations = append(violations, fmt.Sprintf("embed has %d fields, the limit is %d", len(e.Fields), EmbedLimitField))
+	}
+
+	for i, f := range e.Fields {
+		check(fmt.Sprintf("field %d name", i+1), f.Name, EmbedLimitFieldName)
+		check(fmt.Sprintf("field %d value", i+1), f.Value, EmbedLimitFieldValue)
+	}
+
+	if e.Footer != nil {
+		check("footer", e.Footer.Text, EmbedLimitFooter)
+	}
+
+	if e.Author != nil {
+		check("author name", e.Author.Name, EmbedLimitAuthorName)
+	}
+
+	if n := e.Length(); n > EmbedLimit {
+		violations = append(violations, fmt.Sprintf("embed has %d characters in total, the limit is %d", n, EmbedLimit))
+	}
+
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+
+	return nil
+}
+
+// truncate shortens text to at most limit characters, ending it with an ellipsis when anything was cut
+func truncate(text string, limit int) string {
+	if runeCount(text) <= limit {
+		return text
+	}
+
+	if limit <= 0 {
+		return ""
+	}
+
+	runes := []rune(text)
+	return string(runes[:limit-1]) + ellipsis
+}
+
+func runeCount(text string) int {
+	return utf8.RuneCountInString(text)
+}
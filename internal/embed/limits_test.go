@@ -0,0 +1,250 @@
+package embed
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  string
+	}{
+		{name: "empty", text: "", limit: 5, want: ""},
+		{name: "under", text: "abc", limit: 5, want: "abc"},
+		{name: "at", text: "abcde", limit: 5, want: "abcde"},
+		{name: "over", text: "abcdef", limit: 5, want: "abcd…"},
+		{name: "accents at", text: "ééééé", limit: 5, want: "ééééé"},
+		{name: "accents over", text: "éééééé", limit: 5, want: "éééé…"},
+		{name: "cjk over", text: "日本語のテキスト", limit: 4, want: "日本語…"},
+		{name: "emoji at", text: "😀😀😀", limit: 3, want: "😀😀😀"},
+		{name: "emoji over", text: "😀😀😀😀", limit: 3, want: "😀😀…"},
+		{name: "limit 1", text: "😀😀", limit: 1, want: "…"},
+		{name: "limit 0", text: "😀", limit: 0, want: ""},
+		{name: "title limit", text: strings.Repeat("😀", EmbedLimitTitle+1), limit: EmbedLimitTitle, want: strings.Repeat("😀", EmbedLimitTitle-1) + ellipsis},
+		{name: "description limit", text: strings.Repeat("é", EmbedLimitDescription), limit: EmbedLimitDescription, want: strings.Repeat("é", EmbedLimitDescription)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := truncate(test.text, test.limit)
+			if got != test.want {
+				t.Fatalf("truncate(%q, %d) = %q, want %q", test.text, test.limit, got, test.want)
+			}
+
+			if !utf8.ValidString(got) || runeCount(got) > test.limit && test.limit >= 0 {
+				t.Fatalf("truncate(%q, %d) = %q, which is invalid or over the limit", test.text, test.limit, got)
+			}
+		})
+	}
+}
+
+func TestLength(t *testing.T) {
+	e := &Embed{&discordgo.MessageEmbed{
+		Title:       "😀😀",
+		Description: "ééé",
+		Fields:      []*discordgo.MessageEmbedField{{Name: "日本", Value: "a"}},
+		Footer:      &discordgo.MessageEmbedFooter{Text: "👍"},
+		Author:      &discordgo.MessageEmbedAuthor{Name: "ab"},
+		URL:         "https://example.com/not-counted",
+	}}
+
+	if got := e.Length(); got != 11 {
+		t.Fatalf("Length = %d, want 11 characters rather than bytes", got)
+	}
+}
+
+func TestTruncateTotal(t *testing.T) {
+	field := func(value string) *discordgo.MessageEmbedField {
+		return &discordgo.MessageEmbedField{Name: "name", Value: value}
+	}
+
+	tests := []struct {
+		name            string
+		embed           *discordgo.MessageEmbed
+		wantFields      int
+		wantDescription int
+		wantFooter      int
+		wantTitle       int
+	}{
+		{
+			name:            "fits",
+			embed:           &discordgo.MessageEmbed{Title: "title", Description: "description"},
+			wantDescription: 11,
+			wantTitle:       5,
+		},
+		{
+			name: "drops fields from the end first",
+			embed: &discordgo.MessageEmbed{
+				Description: strings.Repeat("é", 1000),
+				Fields:      []*discordgo.MessageEmbedField{field(strings.Repeat("😀", 1000)), field(strings.Repeat("😀", 1000)), field(strings.Repeat("😀", 1000)), field(strings.Repeat("😀", 1000))},
+			},
+			wantFields:      2,
+			wantDescription: 1000,
+		},
+		{
+			name: "then shortens the description",
+			embed: &discordgo.MessageEmbed{
+				Title:       strings.Repeat("t", 100),
+				Description: strings.Repeat("😀", 3950),
+				Fields:      []*discordgo.MessageEmbedField{field("value")},
+				Footer:      &discordgo.MessageEmbedFooter{Text: strings.Repeat("é", 100)},
+			},
+			wantDescription: 3800,
+			wantFooter:      100,
+			wantTitle:       100,
+		},
+		{
+			name: "then the footer",
+			embed: &discordgo.MessageEmbed{
+				Title:       strings.Repeat("t", 100),
+				Description: strings.Repeat("d", 50),
+				Footer:      &discordgo.MessageEmbedFooter{Text: strings.Repeat("😀", 4100)},
+			},
+			wantFooter: 3900,
+			wantTitle:  100,
+		},
+		{
+			name: "then the title",
+			embed: &discordgo.MessageEmbed{
+				Title:       strings.Repeat("😀", 4100),
+				Description: strings.Repeat("d", 50),
+				Footer:      &discordgo.MessageEmbedFooter{Text: strings.Repeat("f", 50)},
+			},
+			wantTitle: 4000,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := (&Embed{test.embed}).TruncateTotal()
+
+			if e.Length() > EmbedLimit {
+				t.Fatalf("Length after TruncateTotal = %d, over the limit of %d", e.Length(), EmbedLimit)
+			}
+
+			footer := 0
+			if e.Footer != nil {
+				footer = runeCount(e.Footer.Text)
+			}
+
+			if len(e.Fields) != test.wantFields || runeCount(e.Description) != test.wantDescription || footer != test.wantFooter || runeCount(e.Title) != test.wantTitle {
+				t.Fatalf("TruncateTotal left %d fields, a description of %d, a footer of %d and a title of %d, want %d, %d, %d and %d",
+					len(e.Fields), runeCount(e.Description), footer, runeCount(e.Title),
+					test.wantFields, test.wantDescription, test.wantFooter, test.wantTitle)
+			}
+
+			for _, text := range []string{e.Title, e.Description} {
+				if !utf8.ValidString(text) {
+					t.Fatalf("TruncateTotal left invalid UTF-8 %q", text)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	fields := func(n int, name string, value string) []*discordgo.MessageEmbedField {
+		f := []*discordgo.MessageEmbedField{}
+		for i := 0; i < n; i++ {
+			f = append(f, &discordgo.MessageEmbedField{Name: name, Value: value})
+		}
+		return f
+	}
+
+	tests := []struct {
+		name  string
+		embed *discordgo.MessageEmbed
+		// want is part of the violation expected, empty when the embed is valid
+		want string
+	}{
+		{name: "valid", embed: &discordgo.MessageEmbed{Title: strings.Repeat("😀", EmbedLimitTitle), Description: strings.Repeat("😀", EmbedLimitDescription)}},
+		{name: "title", embed: &discordgo.MessageEmbed{Title: strings.Repeat("😀", EmbedLimitTitle+1)}, want: "title has 257 characters"},
+		{name: "description", embed: &discordgo.MessageEmbed{Description: strings.Repeat("é", EmbedLimitDescription+1)}, want: "description has 2049 characters"},
+		{name: "field count", embed: &discordgo.MessageEmbed{Fields: fields(EmbedLimitField+1, "n", "v")}, want: "26 fields"},
+		{name: "field name", embed: &discordgo.MessageEmbed{Fields: fields(1, strings.Repeat("😀", EmbedLimitFieldName+1), "v")}, want: "field 1 name has 257 characters"},
+		{name: "field value", embed: &discordgo.MessageEmbed{Fields: fields(1, "n", strings.Repeat("😀", EmbedLimitFieldValue+1))}, want: "field 1 value has 1025 characters"},
+		{name: "footer", embed: &discordgo.MessageEmbed{Footer: &discordgo.MessageEmbedFooter{Text: strings.Repeat("é", EmbedLimitFooter+1)}}, want: "footer has 2049 characters"},
+		{name: "author", embed: &discordgo.MessageEmbed{Author: &discordgo.MessageEmbedAuthor{Name: strings.Repeat("é", EmbedLimitAuthorName+1)}}, want: "author name has 257 characters"},
+		{
+			name: "total",
+			embed: &discordgo.MessageEmbed{
+				Description: strings.Repeat("😀", EmbedLimitDescription),
+				Footer:      &discordgo.MessageEmbedFooter{Text: strings.Repeat("😀", EmbedLimitFooter)},
+			},
+			want: "4096 characters in total",
+		},
+		{name: "utf-8", embed: &discordgo.MessageEmbed{Title: "\xff"}, want: "not valid UTF-8"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := (&Embed{test.embed}).Validate()
+
+			if test.want == "" {
+				if err != nil {
+					t.Fatalf("Validate = %v, want nil", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Validate = %v, want a *ValidationError", err)
+			}
+
+			if len(validationErr.Violations) != 1 || !strings.Contains(validationErr.Violations[0], test.want) {
+				t.Fatalf("Validate violations = %q, want one containing %q", validationErr.Violations, test.want)
+			}
+		})
+	}
+}
+
+func TestMessageValidate(t *testing.T) {
+	options := func(n int) []discordgo.SelectMenuOption {
+		o := []discordgo.SelectMenuOption{}
+		for i := 0; i < n; i++ {
+			o = append(o, discordgo.SelectMenuOption{Label: "option", Value: strings.Repeat("v", i+1)})
+		}
+		return o
+	}
+
+	tests := []struct {
+		name string
+		msg  *Message
+		// want is part of the violation expected, empty when the message is valid
+		want string
+	}{
+		{name: "valid", msg: NewMessage().AddSelectMenu("menu", "pick", options(ComponentLimitSelectOptions)...)},
+		{name: "select options", msg: NewMessage().AddSelectMenu("menu", "pick", options(ComponentLimitSelectOptions+1)...), want: "has 26 options"},
+		{name: "custom id", msg: NewMessage().AddButton("button", discordgo.PrimaryButton, strings.Repeat("x", ComponentLimitCustomID+1)), want: "longer than 100 characters"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.msg.Validate()
+
+			if test.want == "" {
+				if err != nil {
+					t.Fatalf("Validate = %v, want nil", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Validate = %v, want a *ValidationError", err)
+			}
+
+			if len(validationErr.Violations) != 1 || !strings.Contains(validationErr.Violations[0], test.want) {
+				t.Fatalf("Validate violations = %q, want one containing %q", validationErr.Violations, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package embed
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Constants for message component limits
+const (
+	ComponentLimitRows          = 5
+	ComponentLimitButtons       = 5
+	ComponentLimitCustomID      = 100
+	ComponentLimitSelectOptions = 25
+	ComponentLimitLabel         = 80
+)
+
+// customIDSeparator separates a component's handler name from its state in custom ids
+const customIDSeparator = ":"
+
+// Message builds a message with embeds and interactive components
+type Message struct {
+	*discordgo.MessageSend
+}
+
+// NewMessage returns a new message object
+func NewMessage() *Message {
+	return &Message{&discordgo.MessageSend{}}
+}
+
+// SetContent sets the text shown above the message's embeds
+func (m *Message) SetContent(content string) *Message {
+	m.Content = content
+	return m
+}
+
+// AddEmbed adds an embed to the message
+func (m *Message) AddEmbed(embed *discordgo.MessageEmbed) *Message {
+	m.Embeds = append(m.Embeds, embed)
+	return m
+}
+
+// AddButton adds a button to the last row of buttons, starting a new row when it is full
+func (m *Message) AddButton(label string, style discordgo.ButtonStyle, customID string) *Message {
+	return m.addButton(discordgo.Button{
+		Label:    truncate(label, ComponentLimitLabel),
+		Style:    style,
+		CustomID: customID,
+	})
+}
+
+// AddLinkButton adds a button that opens a URL
+func (m *Message) AddLinkButton(label string, URL string) *Message {
+	return m.addButton(discordgo.Button{
+		Label: truncate(label, ComponentLimitLabel),
+		Style: discordgo.LinkButton,
+		URL:   URL,
+	})
+}
+
+func (m *Message) addButton(button discordgo.Button) *Message {
+	if len(m.Components) > 0 {
+		if row, ok := m.Components[len(m.Components)-1].(discordgo.ActionsRow); ok && len(row.Components) < ComponentLimitButtons && !isSelectRow(row) {
+			row.Components = append(row.Components, button)
+			m.Components[len(m.Components)-1] = row
+			return m
+		}
+	}
+
+	m.Components = append(m.Components, discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{button},
+	})
+	return m
+}
+
+// AddSelectMenu adds a select menu on its own row.
+// Options past the limit are kept so Validate can report them, callers page through long lists instead.
+func (m *Message) AddSelectMenu(customID string, placeholder string, options ...discordgo.SelectMenuOption) *Message {
+	for i := range options {
+		options[i].Label = truncate(options[i].Label, ComponentLimitLabel)
+	}
+
+	m.Components = append(m.Components, discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.SelectMenu{
+				CustomID:    customID,
+				Placeholder: truncate(placeholder, ComponentLimitLabel),
+				Options:     options,
+			},
+		},
+	})
+	return m
+}
+
+// Validate checks the message's embeds and components against Discord's limits
+func (m *Message) Validate() error {
+	violations := []string{}
+
+	for i, e := range m.Embeds {
+		if err := (&Embed{e}).Validate(); err != nil {
+			for _, v := range err.(*ValidationError).Violations {
+				violations = append(violations, fmt.Sprintf("embed %d %s", i+1, v))
+			}
+		}
+	}
+
+	if len(m.Components) > ComponentLimitRows {
+		violations = append(violations, fmt.Sprintf("message has %d component rows, the limit is %d", len(m.Components), ComponentLimitRows))
+	}
+
+	for _, c := range m.Components {
+		row, ok := c.(discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+
+		for _, rc := range row.Components {
+			id := ""
+			switch component := rc.(type) {
+			case discordgo.Button:
+				id = component.CustomID
+			case discordgo.SelectMenu:
+				id = component.CustomID
+
+				if len(component.Options) > ComponentLimitSelectOptions {
+					violations = append(violations, fmt.Sprintf("select menu %q has %d options, the limit is %d", id, len(component.Options), ComponentLimitSelectOptions))
+				}
+			}
+
+			if len(id) > ComponentLimitCustomID {
+				violations = append(violations, fmt.Sprintf("custom id %q is longer than %d characters", id, ComponentLimitCustomID))
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+
+	return nil
+}
+
+// Send validates the message and sends it to a channel
+func (m *Message) Send(s *discordgo.Session, channelID string) (*discordgo.Message, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.ChannelMessageSendComplex(channelID, m.MessageSend)
+}
+
+// CustomID encodes a component handler name and its state into a custom id
+func CustomID(name string, args ...string) string {
+	parts := []string{name}
+	for _, arg := range args {
+		parts = append(parts, url.QueryEscape(arg))
+	}
+
+	return strings.Join(parts, customIDSeparator)
+}
+
+// ParseCustomID decodes a custom id created by CustomID
+func ParseCustomID(customID string) (string, []string) {
+	parts := strings.Split(customID, customIDSeparator)
+	args := []string{}
+
+	for _, part := range parts[1:] {
+		arg, err := url.QueryUnescape(part)
+		if err != nil {
+			arg = part
+		}
+
+		args = append(args, arg)
+	}
+
+	return parts[0], args
+}
+
+// DisableComponent returns a copy of a message's components with the component matching customID disabled
+func DisableComponent(components []discordgo.MessageComponent, customID string) []discordgo.MessageComponent {
+	disabled := make([]discordgo.MessageComponent, 0, len(components))
+
+	for _, c := range components {
+		row, ok := c.(*discordgo.ActionsRow)
+		if !ok {
+			if r, isRow := c.(discordgo.ActionsRow); isRow {
+				row, ok = &r, true
+			}
+		}
+
+		if !ok {
+			disabled = append(disabled, c)
+			continue
+		}
+
+		newRow := discordgo.ActionsRow{}
+		for _, rc := range row.Components {
+			switch component := rc.(type) {
+			case *discordgo.Button:
+				b := *component
+				b.Disabled = b.Disabled || b.CustomID == customID
+				newRow.Components = append(newRow.Components, b)
+			case discordgo.Button:
+				component.Disabled = component.Disabled || component.CustomID == customID
+				newRow.Components = append(newRow.Components, component)
+			case *discordgo.SelectMenu:
+				m := *component
+				m.Disabled = m.Disabled || m.CustomID == customID
+				newRow.Components = append(newRow.Components, m)
+			case discordgo.SelectMenu:
+				component.Disabled = component.Disabled || component.CustomID == customID
+				newRow.Components = append(newRow.Components, component)
+			default:
+				newRow.Components = append(newRow.Components, rc)
+			}
+		}
+
+		disabled = append(disabled, newRow)
+	}
+
+	return disabled
+}
+
+func isSelectRow(row discordgo.ActionsRow) bool {
+	for _, c := range row.Components {
+		if _, ok := c.(discordgo.SelectMenu); ok {
+			return true
+		}
+	}
+
+	return false
+}
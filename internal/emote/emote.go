@@ -1,16 +1,135 @@
 package emote
 
-// Emote represents a emote that has an image
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/SonarBeserk/sophie-go/internal/i18n"
+)
+
+// Emote represents a emote that has an image.
+// Messages are templates such as {{.Sender}} or {{plural .SentCount "person" "people"}},
+// legacy Sprintf-style messages are converted when the emote is compiled.
 type Emote struct {
-	Verb                string
+	Verb string
+	// Description explains what the emote does in help
+	Description string
+	// Aliases are other names for the verb, they share the verb's stats
+	Aliases             []string
+	SenderMessage       string
+	SenderDescription   string
+	ReceiverMessage     string
+	ReceiverDescription string
+	// SelfMessage is used when users target themselves, falling back to SenderMessage
+	SelfMessage string
+	// BotMessage is used when users target the bot, falling back to ReceiverMessage
+	BotMessage string
+	// BlockSelf stops users from targeting themselves
+	BlockSelf bool
+	// Title is an optional template shown above the message
+	Title string
+	// Color is the embed color as a hex string such as "#ff88cc"
+	Color string
+	// ShowAuthor shows the sender's name and avatar above the message
+	ShowAuthor bool
+	// ShowReceiverAvatar shows the receiver's avatar as the embed thumbnail
+	ShowReceiverAvatar bool
+	// Translations holds messages for other locales, keyed by locales such as "de" or "pt-br"
+	Translations map[string]Translation
+
+	templates map[string]map[Field]*template.Template
+	color     *int
+}
+
+// Translation holds an emote's messages for a locale, unset messages fall back to English
+type Translation struct {
+	// Aliases are localized names for the verb
+	Aliases             []string
+	Title               string
 	SenderMessage       string
 	SenderDescription   string
 	ReceiverMessage     string
 	ReceiverDescription string
+	SelfMessage         string
+	BotMessage          string
+}
+
+// EmbedColor returns the emote's embed color, or nil if it doesn't have one
+func (e Emote) EmbedColor() *int {
+	return e.color
+}
+
+// ParseColor parses a hex color such as "#ff88cc" or "ff88cc"
+func ParseColor(color string) (int, error) {
+	hex := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(color), "#"), "0x")
+	if len(hex) != 6 {
+		return 0, fmt.Errorf("invalid color %q, expected a hex color such as #ff88cc", color)
+	}
+
+	c, err := strconv.ParseInt(hex, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid color %q, expected a hex color such as #ff88cc", color)
+	}
+
+	return int(c), nil
+}
+
+// Names returns the verb and every alias it can be used by
+func (e Emote) Names() []string {
+	names := []string{strings.ToLower(e.Verb)}
+	seen := map[string]bool{names[0]: true}
+
+	add := func(aliases []string) {
+		for _, alias := range aliases {
+			alias = strings.ToLower(alias)
+			if alias == "" || seen[alias] {
+				continue
+			}
+
+			seen[alias] = true
+			names = append(names, alias)
+		}
+	}
+
+	add(e.Aliases)
+	for _, translation := range e.Translations {
+		add(translation.Aliases)
+	}
+
+	return names
+}
+
+// LocalizedVerb returns the emote's first alias in a locale, or its verb when it has no name in that language
+func (e Emote) LocalizedVerb(locale string) string {
+	for _, l := range i18n.Fallbacks(locale) {
+		for name, translation := range e.Translations {
+			if i18n.Normalize(name) == l && len(translation.Aliases) > 0 {
+				return translation.Aliases[0]
+			}
+		}
+	}
+
+	return e.Verb
 }
 
 // Gif represents a emote image
 type Gif struct {
 	Verb string
 	URL  string
+	// Weight makes an image more likely to be picked, unset weights count as 1
+	Weight int
+	Tags   []string
+}
+
+// HasTag checks if an image has been tagged with a tag
+func (g Gif) HasTag(tag string) bool {
+	for _, t := range g.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,236 @@
+package emote
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/SonarBeserk/sophie-go/internal/i18n"
+	"github.com/pkg/errors"
+)
+
+// Field identifies one of an emote's message templates
+type Field int
+
+// Message templates an emote can have
+const (
+	FieldSenderMessage Field = iota
+	FieldSenderDescription
+	FieldReceiverMessage
+	FieldReceiverDescription
+	FieldSelfMessage
+	FieldBotMessage
+	FieldTitle
+)
+
+var (
+	fieldNames map[Field]string = map[Field]string{
+		FieldSenderMessage:       "SenderMessage",
+		FieldSenderDescription:   "SenderDescription",
+		FieldReceiverMessage:     "ReceiverMessage",
+		FieldReceiverDescription: "ReceiverDescription",
+		FieldSelfMessage:         "SelfMessage",
+		FieldBotMessage:          "BotMessage",
+		FieldTitle:               "Title",
+	}
+
+	// sprintfArgs maps the positional arguments legacy Sprintf-style messages were given to named fields
+	sprintfArgs map[Field][]string = map[Field][]string{
+		FieldSenderMessage:       {"Sender", "Message"},
+		FieldSenderDescription:   {"Sender", "SentCount", "ReceivedCount"},
+		FieldReceiverMessage:     {"Sender", "Receiver", "Message"},
+		FieldReceiverDescription: {"Receiver", "SentCount", "ReceivedCount"},
+		FieldSelfMessage:         {"Sender", "Message"},
+		FieldBotMessage:          {"Sender", "Receiver", "Message"},
+		FieldTitle:               {"Sender", "Receiver"},
+	}
+
+	sprintfVerb = regexp.MustCompile(`%(?:\[(\d+)\])?([a-z%])`)
+
+	// sampleData is used to execute templates at load time so unknown fields are caught before they reach chat
+	sampleData MessageData = MessageData{
+		Sender:        "Sender",
+		Receiver:      "Receiver",
+		Message:       `"Message"`,
+		SentCount:     2,
+		ReceivedCount: 1,
+	}
+)
+
+func (f Field) String() string {
+	return fieldNames[f]
+}
+
+// MessageData holds the values available to message templates.
+// In descriptions SentCount and ReceivedCount are the counts of the user the description is about.
+type MessageData struct {
+	Sender        string
+	Receiver      string
+	Message       string
+	SentCount     int
+	ReceivedCount int
+}
+
+// Compile parses and validates the emote's message templates for every locale
+func (e *Emote) Compile() error {
+	e.templates = map[string]map[Field]*template.Template{}
+
+	if e.Color != "" {
+		color, err := ParseColor(e.Color)
+		if err != nil {
+			return errors.Wrapf(err, "Error parsing color for %s", e.Verb)
+		}
+
+		e.color = &color
+	}
+
+	locales := map[string]Translation{
+		i18n.DefaultLocale: {
+			SenderMessage:       e.SenderMessage,
+			SenderDescription:   e.SenderDescription,
+			ReceiverMessage:     e.ReceiverMessage,
+			ReceiverDescription: e.ReceiverDescription,
+			SelfMessage:         e.SelfMessage,
+			BotMessage:          e.BotMessage,
+			Title:               e.Title,
+		},
+	}
+
+	for locale, translation := range e.Translations {
+		if !i18n.Valid(locale) {
+			return fmt.Errorf("emote %s has a translation for invalid locale %q", e.Verb, locale)
+		}
+
+		locales[i18n.Normalize(locale)] = translation
+	}
+
+	for locale, translation := range locales {
+		templates := map[Field]*template.Template{}
+
+		for field, text := range translation.fieldTexts() {
+			// Missing translations fall back to English, but English is always compiled
+			if text == "" && locale != i18n.DefaultLocale {
+				continue
+			}
+
+			tmpl, err := compileTemplate(e.Verb+"."+locale+"."+field.String(), locale, field, text)
+			if err != nil {
+				return err
+			}
+
+			templates[field] = tmpl
+		}
+
+		e.templates[locale] = templates
+	}
+
+	return nil
+}
+
+// Render executes one of the emote's message templates in a locale, falling back to English when it hasn't been translated
+func (e Emote) Render(locale string, field Field, data MessageData) (string, error) {
+	var tmpl *template.Template
+
+	for _, l := range i18n.Fallbacks(locale) {
+		if t, ok := e.templates[l][field]; ok {
+			tmpl = t
+			break
+		}
+	}
+
+	if tmpl == nil {
+		return "", fmt.Errorf("emote %s has not been compiled", e.Verb)
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", errors.Wrapf(err, "Error rendering %s for %s", field, e.Verb)
+	}
+
+	return sb.String(), nil
+}
+
+func (t Translation) fieldTexts() map[Field]string {
+	return map[Field]string{
+		FieldSenderMessage:       t.SenderMessage,
+		FieldSenderDescription:   t.SenderDescription,
+		FieldReceiverMessage:     t.ReceiverMessage,
+		FieldReceiverDescription: t.ReceiverDescription,
+		FieldSelfMessage:         t.SelfMessage,
+		FieldBotMessage:          t.BotMessage,
+		FieldTitle:               t.Title,
+	}
+}
+
+// templateFuncs returns the helpers available to templates, with plurals following the rules of a locale
+func templateFuncs(locale string) template.FuncMap {
+	return template.FuncMap{
+		"plural": func(n int, forms ...string) string {
+			return i18n.Plural(locale, n, forms...)
+		},
+		"pluralWord": func(n int, forms ...string) string {
+			return i18n.PluralWord(locale, n, forms...)
+		},
+	}
+}
+
+// compileTemplate parses a message, converting legacy Sprintf-style messages to templates first
+func compileTemplate(name string, locale string, field Field, text string) (*template.Template, error) {
+	if !strings.Contains(text, "{{") && strings.Contains(text, "%") {
+		converted, err := ConvertSprintf(field, text)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error converting %s", name)
+		}
+
+		text = converted
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs(locale)).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error parsing %s", name)
+	}
+
+	if err := tmpl.Execute(ioutil.Discard, sampleData); err != nil {
+		return nil, errors.Wrapf(err, "Error validating %s", name)
+	}
+
+	return tmpl, nil
+}
+
+// ConvertSprintf converts a legacy message using positional Sprintf verbs into a named-field template
+func ConvertSprintf(field Field, text string) (string, error) {
+	args := sprintfArgs[field]
+	next := 0
+	var convErr error
+
+	converted := sprintfVerb.ReplaceAllStringFunc(text, func(verb string) string {
+		m := sprintfVerb.FindStringSubmatch(verb)
+		if m[2] == "%" {
+			return "%"
+		}
+
+		// Explicit indexes also move the implicit argument position, matching fmt
+		arg := next
+		if m[1] != "" {
+			n, _ := strconv.Atoi(m[1])
+			arg = n - 1
+		}
+		next = arg + 1
+
+		if arg < 0 || arg >= len(args) {
+			convErr = fmt.Errorf("%s has no argument %d", field, arg+1)
+			return verb
+		}
+
+		return "{{." + args[arg] + "}}"
+	})
+
+	if convErr != nil {
+		return "", convErr
+	}
+
+	return converted, nil
+}
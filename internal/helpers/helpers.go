@@ -11,7 +11,8 @@ var (
 	userNames map[string]string = map[string]string{}
 )
 
-// IsPrivateChat checks a channel's type to verify if a channel is private
+// IsPrivateChat checks a channel's type to verify if a channel is private.
+// Every guild channel type, including threads, news, forum posts and voice text, counts as a guild channel.
 func IsPrivateChat(s *discordgo.Session, channelID string) (bool, error) {
 	channel, err := s.State.Channel(channelID)
 	if err != nil {
@@ -20,7 +21,19 @@ func IsPrivateChat(s *discordgo.Session, channelID string) (bool, error) {
 		}
 	}
 
-	return channel.Type != discordgo.ChannelTypeGuildText, nil
+	return channel.Type == discordgo.ChannelTypeDM || channel.Type == discordgo.ChannelTypeGroupDM, nil
+}
+
+// HasPermission checks if a user has a permission in a channel, administrators have every permission
+func HasPermission(s *discordgo.Session, userID string, channelID string, permission int64) (bool, error) {
+	perms, err := s.State.UserChannelPermissions(userID, channelID)
+	if err != nil {
+		if perms, err = s.UserChannelPermissions(userID, channelID); err != nil {
+			return false, errors.Wrapf(err, "Error occurred getting permissions for %s", userID)
+		}
+	}
+
+	return perms&permission == permission || perms&discordgo.PermissionAdministrator != 0, nil
 }
 
 // GetUserName looks up a member in a guild by username
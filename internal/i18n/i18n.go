@@ -0,0 +1,57 @@
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultLocale is used when no translation exists for a locale
+const DefaultLocale = "en"
+
+var (
+	localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+)
+
+// Normalize lowercases a locale and uses hyphens as separators, turning "pt_BR" into "pt-br"
+func Normalize(locale string) string {
+	return strings.ToLower(strings.Replace(strings.TrimSpace(locale), "_", "-", -1))
+}
+
+// Valid checks if a locale is a well formed language tag
+func Valid(locale string) bool {
+	return localePattern.MatchString(Normalize(locale))
+}
+
+// Fallbacks returns the locales to try in order for a locale, such as "pt-br", "pt" and then "en"
+func Fallbacks(locale string) []string {
+	locale = Normalize(locale)
+	fallbacks := []string{}
+
+	for locale != "" {
+		fallbacks = append(fallbacks, locale)
+
+		idx := strings.LastIndex(locale, "-")
+		if idx < 0 {
+			break
+		}
+		locale = locale[:idx]
+	}
+
+	if len(fallbacks) == 0 || fallbacks[len(fallbacks)-1] != DefaultLocale {
+		fallbacks = append(fallbacks, DefaultLocale)
+	}
+
+	return fallbacks
+}
+
+// T formats a bot reply in a locale, falling back to English when it hasn't been translated
+func T(locale string, key string, args ...interface{}) string {
+	for _, l := range Fallbacks(locale) {
+		if format, ok := messages[l][key]; ok {
+			return fmt.Sprintf(format, args...)
+		}
+	}
+
+	return key
+}
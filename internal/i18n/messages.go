@@ -0,0 +1,302 @@
+package i18n
+
+// Message keys for bot replies
+const (
+	MsgAvailableEmotes  = "available-emotes"
+	MsgImageOutOfRange  = "image-out-of-range"
+	MsgNoTaggedImages   = "no-tagged-images"
+	MsgCantTargetSelf   = "cant-target-self"
+	MsgLocaleCurrent    = "locale-current"
+	MsgLocaleSet        = "locale-set"
+	MsgLocaleReset      = "locale-reset"
+	MsgLocaleGuildSet   = "locale-guild-set"
+	MsgLocaleGuildReset = "locale-guild-reset"
+	MsgLocaleInvalid    = "locale-invalid"
+	MsgNoPermission     = "no-permission"
+	MsgDidYouMean       = "did-you-mean"
+	MsgThemeCurrent     = "theme-current"
+	MsgOn               = "on"
+	MsgOff              = "off"
+	MsgThemeUpdated     = "theme-updated"
+	MsgThemeReset       = "theme-reset"
+	MsgThemeUsage       = "theme-usage"
+	MsgThemeDefault     = "theme-default"
+	MsgPickEmote        = "pick-emote"
+	MsgPagePrevious     = "page-previous"
+	MsgPageNext         = "page-next"
+	MsgReturnEmote      = "return-emote"
+	MsgReturnNotYours   = "return-not-yours"
+	MsgReturnExpired    = "return-expired"
+	MsgGuildOnly        = "guild-only"
+	MsgOptedOut         = "opted-out"
+	MsgOptOutOn         = "opt-out-on"
+	MsgOptOutOff        = "opt-out-off"
+	MsgOptOutStatus     = "opt-out-status"
+	MsgProfileTitle     = "profile-title"
+	MsgProfileCounts    = "profile-counts"
+	MsgProfileEmpty     = "profile-empty"
+	MsgHelpTitle        = "help-title"
+	MsgHelpFooter       = "help-footer"
+	MsgHelpEmotes       = "help-emotes"
+	MsgHelpUsage        = "help-usage"
+	MsgHelpArguments    = "help-arguments"
+	MsgHelpOptional     = "help-optional"
+	MsgHelpExamples     = "help-examples"
+	MsgHelpAliases      = "help-aliases"
+	MsgHelpPermission   = "help-permission"
+	MsgHelpGuildOnly    = "help-guild-only"
+	MsgHelpUnknown      = "help-unknown"
+	MsgUsage            = "usage"
+	MsgRateLimited      = "rate-limited"
+	MsgHelpSubcommands  = "help-subcommands"
+	MsgArgMissing       = "arg-missing"
+	MsgArgNotNumber     = "arg-not-number"
+	MsgArgNoMember      = "arg-no-member"
+	MsgArgNoChannel     = "arg-no-channel"
+	MsgArgTooMany       = "arg-too-many"
+	MsgArgNotOnOff      = "arg-not-on-off"
+	MsgErrorInternal    = "error-internal"
+	MsgErrorPermission  = "error-permission"
+	MsgErrorDiscord     = "error-discord"
+	MsgReturnInvalid    = "return-invalid"
+)
+
+// messages holds Sprintf formats for bot replies keyed by locale
+var messages map[string]map[string]string = map[string]map[string]string{
+	"en": {
+		MsgAvailableEmotes:  "Available Emotes: %s",
+		MsgImageOutOfRange:  "%s only has images 1 to %d",
+		MsgNoTaggedImages:   "%s has no images tagged %s",
+		MsgCantTargetSelf:   "You can't do that to yourself",
+		MsgLocaleCurrent:    "Your language is %s, this server's language is %s",
+		MsgLocaleSet:        "Your language is now %s",
+		MsgLocaleReset:      "Your language now follows the server's language",
+		MsgLocaleGuildSet:   "This server's language is now %s",
+		MsgLocaleGuildReset: "This server's language now follows the server's Discord settings",
+		MsgLocaleInvalid:    "%s isn't a valid language, try something like en, de or pt-BR",
+		MsgNoPermission:     "You need the Manage Server permission to do that",
+		MsgDidYouMean:       "Unknown command %s, did you mean %s?",
+		MsgThemeCurrent:     "This server's theme: color %s, author %s, receiver avatar %s",
+		MsgOn:               "on",
+		MsgOff:              "off",
+		MsgThemeUpdated:     "This server's theme has been updated",
+		MsgThemeReset:       "This server's theme has been reset",
+		MsgThemeUsage:       "Usage: theme [color #ff88cc | author on/off | avatar on/off | reset]",
+		MsgThemeDefault:     "default",
+		MsgPickEmote:        "Pick an emote",
+		MsgPagePrevious:     "Previous",
+		MsgPageNext:         "Next",
+		MsgReturnEmote:      "%s back",
+		MsgReturnNotYours:   "Only the person who received this can %s them back",
+		MsgReturnExpired:    "It is too late to %s them back",
+		MsgGuildOnly:        "%s only works in servers",
+		MsgOptedOut:         "%s has opted out of emotes",
+		MsgOptOutOn:         "You have opted out, others can no longer target you with emotes",
+		MsgOptOutOff:        "You have opted back in, others can target you with emotes again",
+		MsgOptOutStatus:     "Opted out of emotes: %s. Use optout on or optout off to change it",
+		MsgProfileTitle:     "Emote profile for %s",
+		MsgProfileCounts:    "Sent %d, received %d",
+		MsgProfileEmpty:     "You haven't sent or received any emotes yet",
+		MsgHelpTitle:        "Commands",
+		MsgHelpFooter:       "Use help <command> to learn more about a command",
+		MsgHelpEmotes:       "Emotes",
+		MsgHelpUsage:        "Usage",
+		MsgHelpArguments:    "Arguments",
+		MsgHelpOptional:     "optional",
+		MsgHelpExamples:     "Examples",
+		MsgHelpAliases:      "Aliases",
+		MsgHelpPermission:   "Changing server settings requires %s",
+		MsgHelpGuildOnly:    "Only works in servers",
+		MsgHelpUnknown:      "I don't know a command called %s",
+		MsgUsage:            "Usage: `%s`",
+		MsgRateLimited:      "Slow down, you can use commands again in %s",
+		MsgHelpSubcommands:  "Subcommands",
+		MsgArgMissing:       "%s is missing",
+		MsgArgNotNumber:     "%s must be a number, not %s",
+		MsgArgNoMember:      "I couldn't find a member called %s",
+		MsgArgNoChannel:     "I couldn't find a channel called %s",
+		MsgArgTooMany:       "I don't know what to do with %s",
+		MsgArgNotOnOff:      "%s must be on or off, not %s",
+		MsgErrorInternal:    "Something went wrong running %s, sorry! Please try again later",
+		MsgErrorPermission:  "I don't have permission to do %s here, ask a server admin to check my permissions",
+		MsgErrorDiscord:     "Discord didn't accept %s right now, please try again in a moment",
+		MsgReturnInvalid:    "This button no longer works",
+	},
+	"de": {
+		MsgAvailableEmotes:  "Verfügbare Emotes: %s",
+		MsgImageOutOfRange:  "%s hat nur die Bilder 1 bis %d",
+		MsgNoTaggedImages:   "%s hat keine Bilder mit dem Tag %s",
+		MsgCantTargetSelf:   "Das kannst du nicht mit dir selbst machen",
+		MsgLocaleCurrent:    "Deine Sprache ist %s, die Sprache dieses Servers ist %s",
+		MsgLocaleSet:        "Deine Sprache ist jetzt %s",
+		MsgLocaleReset:      "Deine Sprache folgt jetzt der Sprache des Servers",
+		MsgLocaleGuildSet:   "Die Sprache dieses Servers ist jetzt %s",
+		MsgLocaleGuildReset: "Die Sprache dieses Servers folgt jetzt den Discord-Einstellungen des Servers",
+		MsgLocaleInvalid:    "%s ist keine gültige Sprache, versuche etwas wie en, de oder pt-BR",
+		MsgNoPermission:     "Dafür brauchst du die Berechtigung Server verwalten",
+		MsgDidYouMean:       "Unbekannter Befehl %s, meintest du %s?",
+		MsgThemeCurrent:     "Das Design dieses Servers: Farbe %s, Autor %s, Empfänger-Avatar %s",
+		MsgOn:               "an",
+		MsgOff:              "aus",
+		MsgThemeUpdated:     "Das Design dieses Servers wurde aktualisiert",
+		MsgThemeReset:       "Das Design dieses Servers wurde zurückgesetzt",
+		MsgThemeUsage:       "Verwendung: theme [color #ff88cc | author on/off | avatar on/off | reset]",
+		MsgThemeDefault:     "Standard",
+		MsgPickEmote:        "Wähle ein Emote",
+		MsgPagePrevious:     "Zurück",
+		MsgPageNext:         "Weiter",
+		MsgReturnEmote:      "Zurück %s",
+		MsgReturnNotYours:   "Nur die Person, die das erhalten hat, kann zurück %s",
+		MsgReturnExpired:    "Es ist zu spät, um zurück zu %s",
+		MsgGuildOnly:        "%s funktioniert nur auf Servern",
+		MsgOptedOut:         "%s hat sich von Emotes abgemeldet",
+		MsgOptOutOn:         "Du hast dich abgemeldet, andere können dich nicht mehr mit Emotes erwähnen",
+		MsgOptOutOff:        "Du hast dich wieder angemeldet, andere können dich wieder mit Emotes erwähnen",
+		MsgOptOutStatus:     "Von Emotes abgemeldet: %s. Nutze optout on oder optout off, um das zu ändern",
+		MsgProfileTitle:     "Emote-Profil von %s",
+		MsgProfileCounts:    "%d gesendet, %d erhalten",
+		MsgProfileEmpty:     "Du hast noch keine Emotes gesendet oder erhalten",
+		MsgHelpTitle:        "Befehle",
+		MsgHelpFooter:       "Nutze help <Befehl>, um mehr über einen Befehl zu erfahren",
+		MsgHelpEmotes:       "Emotes",
+		MsgHelpUsage:        "Verwendung",
+		MsgHelpArguments:    "Argumente",
+		MsgHelpOptional:     "optional",
+		MsgHelpExamples:     "Beispiele",
+		MsgHelpAliases:      "Aliase",
+		MsgHelpPermission:   "Zum Ändern der Servereinstellungen wird %s benötigt",
+		MsgHelpGuildOnly:    "Funktioniert nur auf Servern",
+		MsgHelpUnknown:      "Ich kenne keinen Befehl namens %s",
+		MsgUsage:            "Verwendung: `%s`",
+		MsgRateLimited:      "Langsamer, du kannst in %s wieder Befehle nutzen",
+		MsgHelpSubcommands:  "Unterbefehle",
+		MsgArgMissing:       "%s fehlt",
+		MsgArgNotNumber:     "%s muss eine Zahl sein, nicht %s",
+		MsgArgNoMember:      "Ich konnte kein Mitglied namens %s finden",
+		MsgArgNoChannel:     "Ich konnte keinen Kanal namens %s finden",
+		MsgArgTooMany:       "Ich weiß nicht, was ich mit %s machen soll",
+		MsgArgNotOnOff:      "%s muss on oder off sein, nicht %s",
+		MsgErrorInternal:    "Beim Ausführen von %s ist etwas schiefgelaufen, sorry! Bitte versuche es später erneut",
+		MsgErrorPermission:  "Ich habe hier keine Berechtigung für %s, bitte einen Server-Admin, meine Berechtigungen zu prüfen",
+		MsgErrorDiscord:     "Discord hat %s gerade nicht angenommen, bitte versuche es gleich noch einmal",
+		MsgReturnInvalid:    "Dieser Knopf funktioniert nicht mehr",
+	},
+	"es": {
+		MsgAvailableEmotes:  "Emotes disponibles: %s",
+		MsgImageOutOfRange:  "%s solo tiene las imágenes 1 a %d",
+		MsgNoTaggedImages:   "%s no tiene imágenes con la etiqueta %s",
+		MsgCantTargetSelf:   "No puedes hacerte eso a ti mismo",
+		MsgLocaleCurrent:    "Tu idioma es %s, el idioma de este servidor es %s",
+		MsgLocaleSet:        "Tu idioma ahora es %s",
+		MsgLocaleReset:      "Tu idioma ahora sigue el idioma del servidor",
+		MsgLocaleGuildSet:   "El idioma de este servidor ahora es %s",
+		MsgLocaleGuildReset: "El idioma de este servidor ahora sigue la configuración de Discord del servidor",
+		MsgLocaleInvalid:    "%s no es un idioma válido, prueba algo como en, de o pt-BR",
+		MsgNoPermission:     "Necesitas el permiso Gestionar servidor para hacer eso",
+		MsgDidYouMean:       "Comando desconocido %s, ¿quisiste decir %s?",
+		MsgThemeCurrent:     "Tema de este servidor: color %s, autor %s, avatar del receptor %s",
+		MsgOn:               "activado",
+		MsgOff:              "desactivado",
+		MsgThemeUpdated:     "El tema de este servidor se ha actualizado",
+		MsgThemeReset:       "El tema de este servidor se ha restablecido",
+		MsgThemeUsage:       "Uso: theme [color #ff88cc | author on/off | avatar on/off | reset]",
+		MsgThemeDefault:     "predeterminado",
+		MsgPickEmote:        "Elige un emote",
+		MsgPagePrevious:     "Anterior",
+		MsgPageNext:         "Siguiente",
+		MsgReturnEmote:      "Devolver %s",
+		MsgReturnNotYours:   "Solo quien lo recibió puede devolver el %s",
+		MsgReturnExpired:    "Es demasiado tarde para devolver el %s",
+		MsgGuildOnly:        "%s solo funciona en servidores",
+		MsgOptedOut:         "%s ha desactivado los emotes",
+		MsgOptOutOn:         "Has desactivado los emotes, otros ya no pueden usarlos contigo",
+		MsgOptOutOff:        "Has vuelto a activar los emotes, otros pueden usarlos contigo de nuevo",
+		MsgOptOutStatus:     "Emotes desactivados: %s. Usa optout on u optout off para cambiarlo",
+		MsgProfileTitle:     "Perfil de emotes de %s",
+		MsgProfileCounts:    "%d enviados, %d recibidos",
+		MsgProfileEmpty:     "Aún no has enviado ni recibido emotes",
+		MsgHelpTitle:        "Comandos",
+		MsgHelpFooter:       "Usa help <comando> para saber más sobre un comando",
+		MsgHelpEmotes:       "Emotes",
+		MsgHelpUsage:        "Uso",
+		MsgHelpArguments:    "Argumentos",
+		MsgHelpOptional:     "opcional",
+		MsgHelpExamples:     "Ejemplos",
+		MsgHelpAliases:      "Alias",
+		MsgHelpPermission:   "Cambiar la configuración del servidor requiere %s",
+		MsgHelpGuildOnly:    "Solo funciona en servidores",
+		MsgHelpUnknown:      "No conozco ningún comando llamado %s",
+		MsgUsage:            "Uso: `%s`",
+		MsgRateLimited:      "Más despacio, puedes volver a usar comandos en %s",
+		MsgHelpSubcommands:  "Subcomandos",
+		MsgArgMissing:       "Falta %s",
+		MsgArgNotNumber:     "%s debe ser un número, no %s",
+		MsgArgNoMember:      "No encontré a ningún miembro llamado %s",
+		MsgArgNoChannel:     "No encontré ningún canal llamado %s",
+		MsgArgTooMany:       "No sé qué hacer con %s",
+		MsgArgNotOnOff:      "%s debe ser on u off, no %s",
+		MsgErrorInternal:    "Algo salió mal al ejecutar %s, ¡lo siento! Inténtalo de nuevo más tarde",
+		MsgErrorPermission:  "No tengo permiso para hacer %s aquí, pide a un administrador que revise mis permisos",
+		MsgErrorDiscord:     "Discord no aceptó %s ahora mismo, inténtalo de nuevo en un momento",
+		MsgReturnInvalid:    "Este botón ya no funciona",
+	},
+	"fr": {
+		MsgAvailableEmotes:  "Emotes disponibles : %s",
+		MsgImageOutOfRange:  "%s n'a que les images 1 à %d",
+		MsgNoTaggedImages:   "%s n'a aucune image avec le tag %s",
+		MsgCantTargetSelf:   "Tu ne peux pas te faire ça à toi-même",
+		MsgLocaleCurrent:    "Ta langue est %s, la langue de ce serveur est %s",
+		MsgLocaleSet:        "Ta langue est maintenant %s",
+		MsgLocaleReset:      "Ta langue suit maintenant celle du serveur",
+		MsgLocaleGuildSet:   "La langue de ce serveur est maintenant %s",
+		MsgLocaleGuildReset: "La langue de ce serveur suit maintenant les paramètres Discord du serveur",
+		MsgLocaleInvalid:    "%s n'est pas une langue valide, essaie par exemple en, de ou pt-BR",
+		MsgNoPermission:     "Tu as besoin de la permission Gérer le serveur pour faire ça",
+		MsgDidYouMean:       "Commande inconnue %s, voulais-tu dire %s ?",
+		MsgThemeCurrent:     "Thème de ce serveur : couleur %s, auteur %s, avatar du destinataire %s",
+		MsgOn:               "activé",
+		MsgOff:              "désactivé",
+		MsgThemeUpdated:     "Le thème de ce serveur a été mis à jour",
+		MsgThemeReset:       "Le thème de ce serveur a été réinitialisé",
+		MsgThemeUsage:       "Utilisation : theme [color #ff88cc | author on/off | avatar on/off | reset]",
+		MsgThemeDefault:     "par défaut",
+		MsgPickEmote:        "Choisis une emote",
+		MsgPagePrevious:     "Précédent",
+		MsgPageNext:         "Suivant",
+		MsgReturnEmote:      "Rendre le %s",
+		MsgReturnNotYours:   "Seule la personne qui l'a reçu peut rendre le %s",
+		MsgReturnExpired:    "Il est trop tard pour rendre le %s",
+		MsgGuildOnly:        "%s ne fonctionne que sur les serveurs",
+		MsgOptedOut:         "%s a désactivé les emotes",
+		MsgOptOutOn:         "Tu as désactivé les emotes, les autres ne peuvent plus te cibler",
+		MsgOptOutOff:        "Tu as réactivé les emotes, les autres peuvent de nouveau te cibler",
+		MsgOptOutStatus:     "Emotes désactivées : %s. Utilise optout on ou optout off pour changer",
+		MsgProfileTitle:     "Profil d'emotes de %s",
+		MsgProfileCounts:    "%d envoyées, %d reçues",
+		MsgProfileEmpty:     "Tu n'as encore envoyé ni reçu aucune emote",
+		MsgHelpTitle:        "Commandes",
+		MsgHelpFooter:       "Utilise help <commande> pour en savoir plus sur une commande",
+		MsgHelpEmotes:       "Emotes",
+		MsgHelpUsage:        "Utilisation",
+		MsgHelpArguments:    "Arguments",
+		MsgHelpOptional:     "facultatif",
+		MsgHelpExamples:     "Exemples",
+		MsgHelpAliases:      "Alias",
+		MsgHelpPermission:   "Modifier les paramètres du serveur nécessite %s",
+		MsgHelpGuildOnly:    "Ne fonctionne que sur les serveurs",
+		MsgHelpUnknown:      "Je ne connais aucune commande appelée %s",
+		MsgUsage:            "Utilisation : `%s`",
+		MsgRateLimited:      "Doucement, tu pourras de nouveau utiliser des commandes dans %s",
+		MsgHelpSubcommands:  "Sous-commandes",
+		MsgArgMissing:       "%s est manquant",
+		MsgArgNotNumber:     "%s doit être un nombre, pas %s",
+		MsgArgNoMember:      "Je n'ai trouvé aucun membre appelé %s",
+		MsgArgNoChannel:     "Je n'ai trouvé aucun salon appelé %s",
+		MsgArgTooMany:       "Je ne sais pas quoi faire de %s",
+		MsgArgNotOnOff:      "%s doit être on ou off, pas %s",
+		MsgErrorInternal:    "Quelque chose s'est mal passé avec %s, désolée ! Réessaie plus tard",
+		MsgErrorPermission:  "Je n'ai pas la permission de faire %s ici, demande à un admin du serveur de vérifier mes permissions",
+		MsgErrorDiscord:     "Discord n'a pas accepté %s pour le moment, réessaie dans un instant",
+		MsgReturnInvalid:    "Ce bouton ne fonctionne plus",
+	},
+}
@@ -0,0 +1,85 @@
+package i18n
+
+import "strconv"
+
+// pluralRules picks which plural form to use for a count, forms are ordered as in CLDR (one, few, many)
+var pluralRules map[string]func(n int) int = map[string]func(n int) int{
+	"en": oneOther,
+	"de": oneOther,
+	"es": oneOther,
+	"it": oneOther,
+	"nl": oneOther,
+	"pt": oneOther,
+	"sv": oneOther,
+	"fr": func(n int) int {
+		if n == 0 || n == 1 {
+			return 0
+		}
+		return 1
+	},
+	"pl": func(n int) int {
+		switch {
+		case n == 1:
+			return 0
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return 1
+		default:
+			return 2
+		}
+	},
+	"ru": slavic,
+	"uk": slavic,
+	"ja": noPlural,
+	"ko": noPlural,
+	"zh": noPlural,
+}
+
+func oneOther(n int) int {
+	if n == 1 {
+		return 0
+	}
+	return 1
+}
+
+func slavic(n int) int {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return 0
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return 1
+	default:
+		return 2
+	}
+}
+
+func noPlural(n int) int {
+	return 0
+}
+
+// PluralWord returns the form of a word for a count in a locale.
+// Missing forms fall back to the last one given, so English style singular and plural pairs work everywhere.
+func PluralWord(locale string, n int, forms ...string) string {
+	if len(forms) == 0 {
+		return ""
+	}
+
+	rule := oneOther
+	for _, l := range Fallbacks(locale) {
+		if r, ok := pluralRules[l]; ok {
+			rule = r
+			break
+		}
+	}
+
+	idx := rule(n)
+	if idx >= len(forms) {
+		idx = len(forms) - 1
+	}
+
+	return forms[idx]
+}
+
+// Plural formats a count with the form of a word for it in a locale, such as "1 person" or "2 people"
+func Plural(locale string, n int, forms ...string) string {
+	return strconv.Itoa(n) + " " + PluralWord(locale, n, forms...)
+}
@@ -0,0 +1,123 @@
+package linkcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Result is the outcome of checking a single link
+type Result struct {
+	URL string
+	// FinalURL is the URL the link resolved to after following redirects
+	FinalURL   string
+	StatusCode int
+	Err        error
+}
+
+// Dead reports whether the link no longer serves an image
+func (r Result) Dead() bool {
+	return r.Err != nil || r.StatusCode != http.StatusOK
+}
+
+// Redirected reports whether the link resolved to a different URL
+func (r Result) Redirected() bool {
+	return r.FinalURL != "" && r.FinalURL != r.URL
+}
+
+// Checker checks whether image links are still alive
+type Checker struct {
+	Client  *http.Client
+	Workers int
+}
+
+// NewChecker creates a checker with a request timeout and a number of concurrent workers
+func NewChecker(timeout time.Duration, workers int) *Checker {
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &Checker{
+		Client:  &http.Client{Timeout: timeout},
+		Workers: workers,
+	}
+}
+
+// Check issues a HEAD request for a link, falling back to GET for servers that don't support HEAD
+func (c *Checker) Check(ctx context.Context, URL string) Result {
+	res := c.do(ctx, http.MethodHead, URL)
+	if res.Err == nil && (res.StatusCode == http.StatusMethodNotAllowed || res.StatusCode == http.StatusForbidden) {
+		res = c.do(ctx, http.MethodGet, URL)
+	}
+
+	return res
+}
+
+func (c *Checker) do(ctx context.Context, method string, URL string) Result {
+	res := Result{URL: URL}
+
+	req, err := http.NewRequest(method, URL, nil)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	if method == http.MethodGet {
+		req.Header.Set("Range", "bytes=0-511")
+	}
+
+	resp, err := c.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	defer resp.Body.Close()
+
+	// Drain a little of the body so the connection can be reused
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 512))
+
+	res.FinalURL = resp.Request.URL.String()
+	res.StatusCode = resp.StatusCode
+
+	if resp.StatusCode == http.StatusPartialContent {
+		res.StatusCode = http.StatusOK
+	}
+
+	// Dead hosts commonly redirect to a landing page instead of returning an error
+	contentType := resp.Header.Get("Content-Type")
+	if res.StatusCode == http.StatusOK && contentType != "" && !strings.HasPrefix(contentType, "image/") {
+		res.Err = fmt.Errorf("unexpected content type %s", contentType)
+	}
+
+	return res
+}
+
+// CheckAll checks every link concurrently, returning results in the same order as the links
+func (c *Checker) CheckAll(ctx context.Context, URLs []string) []Result {
+	results := make([]Result, len(URLs))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < c.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				results[idx] = c.Check(ctx, URLs[idx])
+			}
+		}()
+	}
+
+	for i := range URLs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
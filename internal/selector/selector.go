@@ -0,0 +1,113 @@
+package selector
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/SonarBeserk/sophie-go/internal/emote"
+)
+
+// Selector picks weighted random images while avoiding ones shown recently under the same key
+type Selector struct {
+	window int
+	recent map[string][]string
+	rand   *rand.Rand
+	mu     sync.Mutex
+}
+
+// New creates a selector that avoids repeating any of the last window images shown
+func New(window int) *Selector {
+	return &Selector{
+		window: window,
+		recent: map[string][]string{},
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Pick chooses an image for a key, such as a guild and verb, and remembers it as shown
+func (s *Selector) Pick(key string, gifs []emote.Gif) (emote.Gif, bool) {
+	if len(gifs) == 0 {
+		return emote.Gif{}, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Never exclude every image, one must always remain to pick from
+	window := s.window
+	if window > len(gifs)-1 {
+		window = len(gifs) - 1
+	}
+
+	recent := s.recent[key]
+	if len(recent) > window {
+		recent = recent[len(recent)-window:]
+	}
+
+	excluded := map[string]bool{}
+	for _, url := range recent {
+		excluded[url] = true
+	}
+
+	candidates := []emote.Gif{}
+	total := 0
+
+	for _, gif := range gifs {
+		if excluded[gif.URL] {
+			continue
+		}
+
+		candidates = append(candidates, gif)
+		total += weight(gif)
+	}
+
+	// Images can fall out of the list between picks, so fall back to everything
+	if len(candidates) == 0 {
+		candidates = gifs
+		total = 0
+		for _, gif := range gifs {
+			total += weight(gif)
+		}
+	}
+
+	r := s.rand.Intn(total)
+	picked := candidates[len(candidates)-1]
+
+	for _, gif := range candidates {
+		r -= weight(gif)
+		if r < 0 {
+			picked = gif
+			break
+		}
+	}
+
+	s.remember(key, picked.URL)
+	return picked, true
+}
+
+// Remember records an image as shown for a key, such as when a user asks for a specific image
+func (s *Selector) Remember(key string, url string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.remember(key, url)
+}
+
+func (s *Selector) remember(key string, url string) {
+	recent := append(s.recent[key], url)
+	if len(recent) > s.window {
+		recent = recent[len(recent)-s.window:]
+	}
+
+	s.recent[key] = recent
+}
+
+// weight returns an image's weight, treating unset weights as 1
+func weight(gif emote.Gif) int {
+	if gif.Weight <= 0 {
+		return 1
+	}
+
+	return gif.Weight
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// LocalConfig configures a directory of images served by the bot
+type LocalConfig struct {
+	Directory string
+	Prefix    string
+	// BaseURL is the public URL the directory is served from
+	BaseURL string
+}
+
+// Local stores objects in a directory on disk
+type Local struct {
+	config LocalConfig
+}
+
+// NewLocal creates a storage backed by a local directory
+func NewLocal(config LocalConfig) (*Local, error) {
+	if config.Directory == "" {
+		return nil, errors.New("A directory is required")
+	}
+
+	if config.BaseURL == "" {
+		return nil, errors.New("A public base url is required")
+	}
+
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+
+	err := os.MkdirAll(config.Directory, 0755)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error creating directory %s", config.Directory)
+	}
+
+	return &Local{config: config}, nil
+}
+
+// Put writes an object into the directory
+func (l *Local) Put(ctx context.Context, key string, body io.Reader, opts PutOptions) (string, error) {
+	key = joinKey(l.config.Prefix, key)
+	fileName := filepath.Join(l.config.Directory, filepath.FromSlash(key))
+
+	err := os.MkdirAll(filepath.Dir(fileName), 0755)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error creating directory for %s", key)
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(fileName), filepath.Base(fileName)+".*.tmp")
+	if err != nil {
+		return "", errors.Wrapf(err, "Error creating temporary file for %s", key)
+	}
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", errors.Wrapf(err, "Error writing %s", key)
+	}
+
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", errors.Wrapf(err, "Error setting permissions for %s", key)
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", errors.Wrapf(err, "Error closing %s", key)
+	}
+
+	if err := os.Rename(tmp.Name(), fileName); err != nil {
+		os.Remove(tmp.Name())
+		return "", errors.Wrapf(err, "Error moving %s into place", key)
+	}
+
+	return l.config.BaseURL + "/" + key, nil
+}
+
+// Hosts reports whether a URL is served from the directory
+func (l *Local) Hosts(URL string) bool {
+	return strings.HasPrefix(URL, l.config.BaseURL+"/")
+}
+
+// LocalHandler serves a local image directory, setting cacheControl on every response when provided
+func LocalHandler(directory string, cacheControl string) http.Handler {
+	files := http.FileServer(http.Dir(directory))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Don't expose directory listings
+		if strings.HasSuffix(r.URL.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+
+		if cacheControl != "" {
+			w.Header().Set("Cache-Control", cacheControl)
+		}
+
+		files.ServeHTTP(w, r)
+	})
+}
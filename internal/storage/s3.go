@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/pkg/errors"
+)
+
+// S3Config configures an S3 or S3-compatible bucket
+type S3Config struct {
+	Bucket string
+	Prefix string
+	Region string
+	// Endpoint overrides the AWS endpoint for S3-compatible services such as MinIO or R2
+	Endpoint string
+	// PathStyle addresses the bucket in the path instead of the host name
+	PathStyle    bool
+	ACL          string
+	CacheControl string
+}
+
+// S3 stores objects in an S3 bucket
+type S3 struct {
+	config   S3Config
+	uploader *s3manager.Uploader
+}
+
+// NewS3 creates a storage backed by an S3 or S3-compatible bucket
+func NewS3(config S3Config) (*S3, error) {
+	if config.Bucket == "" {
+		return nil, errors.New("A bucket name is required")
+	}
+
+	awsConfig := aws.NewConfig()
+
+	if config.Region != "" {
+		awsConfig = awsConfig.WithRegion(config.Region)
+	}
+
+	if config.Endpoint != "" {
+		awsConfig = awsConfig.WithEndpoint(config.Endpoint)
+	}
+
+	if config.PathStyle {
+		awsConfig = awsConfig.WithS3ForcePathStyle(true)
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *awsConfig,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating AWS session")
+	}
+
+	// The region may come from the shared AWS config, and is needed to know the bucket's hosts
+	if config.Region == "" {
+		config.Region = aws.StringValue(sess.Config.Region)
+	}
+
+	return &S3{
+		config:   config,
+		uploader: s3manager.NewUploader(sess),
+	}, nil
+}
+
+// Put uploads an object to the bucket
+func (s *S3) Put(ctx context.Context, key string, body io.Reader, opts PutOptions) (string, error) {
+	input := &s3manager.UploadInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(joinKey(s.config.Prefix, key)),
+		Body:   body,
+	}
+
+	if s.config.ACL != "" {
+		input.ACL = aws.String(s.config.ACL)
+	}
+
+	cacheControl := opts.CacheControl
+	if cacheControl == "" {
+		cacheControl = s.config.CacheControl
+	}
+
+	if cacheControl != "" {
+		input.CacheControl = aws.String(cacheControl)
+	}
+
+	if opts.ContentType != "" {
+		input.ContentType = aws.String(opts.ContentType)
+	}
+
+	output, err := s.uploader.UploadWithContext(ctx, input)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error uploading %s", key)
+	}
+
+	return output.Location, nil
+}
+
+// Hosts reports whether a URL points at the bucket, either virtual-hosted or path-style
+func (s *S3) Hosts(URL string) bool {
+	u, err := url.Parse(URL)
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(u.Host)
+
+	for _, serviceHost := range s.serviceHosts() {
+		if host == s.config.Bucket+"."+serviceHost {
+			return true
+		}
+
+		if host == serviceHost && strings.HasPrefix(u.Path, "/"+s.config.Bucket+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// serviceHosts returns the hosts the bucket can be reached under, either with the bucket in front or in the path
+func (s *S3) serviceHosts() []string {
+	if s.config.Endpoint != "" {
+		endpoint, err := url.Parse(s.config.Endpoint)
+		if err != nil || endpoint.Host == "" {
+			return nil
+		}
+
+		return []string{strings.ToLower(endpoint.Host)}
+	}
+
+	hosts := []string{}
+	if s.config.Region != "" {
+		region := strings.ToLower(s.config.Region)
+		hosts = append(hosts, "s3."+region+".amazonaws.com", "s3-"+region+".amazonaws.com")
+	}
+
+	// The global endpoint is used for us-east-1 and redirects for every other region
+	return append(hosts, "s3.amazonaws.com")
+}
@@ -0,0 +1,39 @@
+package storage
+
+import "testing"
+
+func TestS3Hosts(t *testing.T) {
+	aws := &S3{config: S3Config{Bucket: "emotes", Region: "us-west-2"}}
+	minio := &S3{config: S3Config{Bucket: "emotes", Endpoint: "https://minio.example.com:9000", PathStyle: true}}
+
+	tests := []struct {
+		name  string
+		store *S3
+		url   string
+		want  bool
+	}{
+		{name: "regional virtual-hosted", store: aws, url: "https://emotes.s3.us-west-2.amazonaws.com/hug.gif", want: true},
+		{name: "legacy regional virtual-hosted", store: aws, url: "https://emotes.s3-us-west-2.amazonaws.com/hug.gif", want: true},
+		{name: "global virtual-hosted", store: aws, url: "https://emotes.s3.amazonaws.com/hug.gif", want: true},
+		{name: "regional path-style", store: aws, url: "https://s3.us-west-2.amazonaws.com/emotes/hug.gif", want: true},
+		{name: "host case", store: aws, url: "https://EMOTES.S3.US-WEST-2.AMAZONAWS.COM/hug.gif", want: true},
+		{name: "other region", store: aws, url: "https://emotes.s3.eu-west-1.amazonaws.com/hug.gif"},
+		{name: "other bucket", store: aws, url: "https://emotes-old.s3.us-west-2.amazonaws.com/hug.gif"},
+		{name: "bucket prefix on another site", store: aws, url: "https://emotes.example.com/hug.gif"},
+		{name: "bucket path on another site", store: aws, url: "https://example.com/emotes/hug.gif"},
+		{name: "lookalike host", store: aws, url: "https://emotes.s3.us-west-2.amazonaws.com.example.com/hug.gif"},
+		{name: "endpoint path-style", store: minio, url: "https://minio.example.com:9000/emotes/hug.gif", want: true},
+		{name: "endpoint virtual-hosted", store: minio, url: "https://emotes.minio.example.com:9000/hug.gif", want: true},
+		{name: "endpoint suffix", store: minio, url: "https://evilminio.example.com:9000/emotes/hug.gif"},
+		{name: "endpoint other bucket", store: minio, url: "https://minio.example.com:9000/other/hug.gif"},
+		{name: "invalid url", store: aws, url: "://"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.store.Hosts(test.url); got != test.want {
+				t.Fatalf("Hosts(%q) = %v, want %v", test.url, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"path"
+)
+
+// DefaultCacheControl is the Cache-Control header emote images are stored and served with unless configured otherwise
+const DefaultCacheControl = "public, max-age=86400"
+
+// PutOptions holds the metadata stored alongside an object
+type PutOptions struct {
+	ContentType  string
+	CacheControl string
+}
+
+// Storage stores emote images somewhere they can be served from
+type Storage interface {
+	// Put stores the contents of body under key and returns the public URL of the stored object
+	Put(ctx context.Context, key string, body io.Reader, opts PutOptions) (string, error)
+	// Hosts reports whether a URL already points at this storage
+	Hosts(URL string) bool
+}
+
+// joinKey prefixes a key, avoiding doubled or leading slashes
+func joinKey(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return path.Join(prefix, key)
+}